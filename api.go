@@ -19,20 +19,48 @@ package openzwave
 // #include "api.h"
 import "C"
 
+import (
+	"context"
+	"sync"
+	"time"
+	"unsafe"
+)
+
 // A value-less type that is used to represent signals generated by the API, particularly quit signals used to
 // ask an EventLoop to quit.
 
 type api struct {
-	loop              EventLoop
-	callback          NotificationCallback
-	eventCallback     EventCallback
-	deviceFactory     DeviceFactory
-	device            string
-	quitEventLoop     chan int
-	shutdownDriver    chan int
-	logger            Logger
-	networks          map[uint32]*network
-	quitDeviceMonitor chan int
+	dropped            uint64 // accessed atomically, so must remain first to be 64-bit aligned
+	loop               EventLoop
+	callback           NotificationCallback
+	eventCallback      EventCallback
+	deviceFactory      DeviceFactory
+	configPath         string
+	userPath           string
+	device             string
+	additionalDevices  []string
+	deviceMustExist    bool
+	quitEventLoop      chan int
+	shutdownDriver     chan int
+	logger             Logger
+	networks           map[uint32]*network
+	model              sync.RWMutex // guards the networks, nodes and values, changed by the notification thread and removeDriver
+	quitDeviceMonitor  chan int
+	controllerCallback ControllerCallback
+	ozwLogHandler      OZWLogHandler
+	subscriptions      subscriptions
+	notificationBuffer int
+	overflowPolicy     OverflowPolicy
+	pendingWrites      pendingWrites
+	valueCache         valueCache
+	driverReady        driverReadyCallbacks
+	homeSubscriptions  homeSubscriptions
+	managerStopped     chan struct{} // closed when the manager of the current run has stopped
+	managerStoppedLock sync.Mutex
+	debouncer          debouncer
+	nodeChanged        nodeChangedCallbacks
+	drainTimeout       time.Duration
+	simulator          *SimConfig // the simulated network to run against, if any
 }
 
 //
@@ -46,8 +74,353 @@ type API interface {
 	// the API logger
 	Logger() Logger
 
+	// the name of the device the API is bound to
+	DeviceName() string
+
+	// the names of all the devices the API is bound to, including those added with AddDeviceName
+	DeviceNames() []string
+
 	// Shutdown the event loop
 	Shutdown(exit int)
+
+	// Remove the driver and stop the manager, answering once they have stopped
+	Close() error
+
+	// Restart the driver for the current device, blocking until it is ready again
+	RestartDriver() error
+
+	// Register a function that is called with the home id of each network whose driver becomes ready
+	OnDriverReady(callback func(homeId uint32))
+
+	// Register a function that is called with each node that changes, coalescing bursts of changes
+	OnNodeChanged(callback func(homeId uint32, nodeId uint8))
+
+	// Answer true once the initial queries of the network are complete
+	IsNetworkReady(homeId uint32) bool
+
+	// Answer the value recorded by the value cache from the last change of the value
+	CachedValue(homeId uint32, valueId uint64) (interface{}, bool)
+
+	// Take a copy of the networks, nodes and values known to the API
+	Snapshot() (NetworkSnapshot, error)
+
+	// Subscribe to the notifications accepted by the filter
+	Subscribe(filter NotificationFilter) <-chan Notification
+
+	// Answer a channel that receives the notifications of a network, closed when its driver is removed
+	NotificationsForHome(homeId uint32) <-chan Notification
+
+	// Stop delivery to a channel returned by Subscribe, then close the channel
+	Unsubscribe(notifications <-chan Notification)
+
+	// Answer the number of notifications discarded because a subscriber's channel was full
+	GetDroppedNotifications() uint64
+
+	// Wait until the queries of a node are complete, or the context is done
+	WaitForNodeReady(ctx context.Context, homeId uint32, nodeId uint8) error
+
+	// Wait for the next VALUE_CHANGED notification for a value, or until the context is done
+	WaitForValueChange(ctx context.Context, homeId uint32, valueId uint64) (Notification, error)
+
+	// Set the value of a boolean value
+	SetBoolValue(homeId uint32, valueId uint64, value bool) error
+
+	// Set the value of a boolean value, then wait until the device reports that it has applied the change
+	SetBoolValueAck(ctx context.Context, homeId uint32, valueId uint64, value bool) error
+
+	// Set the value of a boolean value, retrying with exponential backoff until the device applies the change
+	SetBoolValueRetry(homeId uint32, valueId uint64, value bool, attempts int, backoff time.Duration) error
+
+	// Set a value once the node that owns it is awake. The type of value selects the setter.
+	QueueSetValueOnWake(homeId uint32, valueId uint64, value interface{}) error
+
+	// Answer the writes that are queued until a node is awake
+	PendingWrites(homeId uint32, nodeId uint8) []PendingWrite
+
+	// Set the value of an integer value
+	SetIntValue(homeId uint32, valueId uint64, value int32) error
+
+	// Set the value of a byte value
+	SetByteValue(homeId uint32, valueId uint64, value uint8) error
+
+	// Set the value of a short value
+	SetShortValue(homeId uint32, valueId uint64, value int16) error
+
+	// Set the value of a string value
+	SetStringValue(homeId uint32, valueId uint64, value string) error
+
+	// Get the value of a boolean value. ok is false if the value is not yet available.
+	GetBoolValue(homeId uint32, valueId uint64) (value bool, ok bool, err error)
+
+	// Get the value of an integer value. ok is false if the value is not yet available.
+	GetIntValue(homeId uint32, valueId uint64) (value int32, ok bool, err error)
+
+	// Get the value of a byte value. ok is false if the value is not yet available.
+	GetByteValue(homeId uint32, valueId uint64) (value uint8, ok bool, err error)
+
+	// Get the value of a string value. ok is false if the value is not yet available.
+	GetStringValue(homeId uint32, valueId uint64) (value string, ok bool, err error)
+
+	// Get the value of a decimal value. ok is false if the value is not yet available.
+	GetValueAsFloat(homeId uint32, valueId uint64) (float64, bool, error)
+
+	// Set the value of a decimal value, rounded to the precision of the value
+	SetDecimalValue(homeId uint32, valueId uint64, value float64) error
+
+	// Get the bytes of a raw value
+	GetValueAsRaw(homeId uint32, valueId uint64) ([]byte, error)
+
+	// Set the bytes of a raw value
+	SetRawValue(homeId uint32, valueId uint64, value []byte) error
+
+	// Get any value rendered as a string, regardless of its underlying type
+	GetValueAsString(homeId uint32, valueId uint64) (string, error)
+
+	// Get the human-readable label of a value
+	GetValueLabel(homeId uint32, valueId uint64) (string, error)
+
+	// Get the help text of a value
+	GetValueHelp(homeId uint32, valueId uint64) (string, error)
+
+	// Get the units of a value. Values without units answer an empty string.
+	GetValueUnits(homeId uint32, valueId uint64) (string, error)
+
+	// Get the minimum of a value. ErrNoValueRange is returned if the value does not define a range.
+	GetValueMin(homeId uint32, valueId uint64) (int32, error)
+
+	// Get the maximum of a value. ErrNoValueRange is returned if the value does not define a range.
+	GetValueMax(homeId uint32, valueId uint64) (int32, error)
+
+	// Answer true if the value is read only. An error is returned if the value is unknown.
+	IsValueReadOnly(homeId uint32, valueId uint64) (bool, error)
+
+	// Answer true if the value is write only. An error is returned if the value is unknown.
+	IsValueWriteOnly(homeId uint32, valueId uint64) (bool, error)
+
+	// Get the items of a list value
+	GetValueListItems(homeId uint32, valueId uint64) ([]string, error)
+
+	// Get the currently selected item of a list value
+	GetValueListSelection(homeId uint32, valueId uint64) (string, error)
+
+	// Select an item of a list value
+	SetValueListSelection(homeId uint32, valueId uint64, item string) error
+
+	// Put the controller into inclusion mode. Progress is reported to the ControllerCallback.
+	BeginInclusion(homeId uint32, secure bool) error
+
+	// Put the controller into exclusion mode. Progress is reported to the ControllerCallback.
+	BeginExclusion(homeId uint32) error
+
+	// Answer true if the controller believes the node has failed
+	HasNodeFailed(homeId uint32, nodeId uint8) (bool, error)
+
+	// Remove a failed node from the network. An error is returned if the node has not failed.
+	RemoveFailedNode(homeId uint32, nodeId uint8) error
+
+	// Replace a failed node with a new device. Progress is reported to the ControllerCallback.
+	ReplaceFailedNode(homeId uint32, nodeId uint8) error
+
+	// Ask a node to broadcast its node information frame. Answers false if the command was not queued.
+	SendNodeInformation(homeId uint32, nodeId uint8) (bool, error)
+
+	// Assign a node a return route to the controller. Answers false if the command was not queued.
+	AssignReturnRoute(homeId uint32, nodeId uint8) (bool, error)
+
+	// Delete all the return routes of a node. Answers false if the command was not queued.
+	DeleteAllReturnRoutes(homeId uint32, nodeId uint8) (bool, error)
+
+	// Heal the routes of a node, optionally updating its return routes
+	HealNetworkNode(homeId uint32, nodeId uint8, doRR bool) error
+
+	// Heal the routes of every node in the network, optionally updating their return routes
+	HealNetwork(homeId uint32, doRR bool) error
+
+	// Send test frames to a node. The outcomes are reported as notifications and node statistics.
+	TestNetworkNode(homeId uint32, nodeId uint8, count uint32) error
+
+	// Send test frames to every node in the network.
+	TestNetwork(homeId uint32, count uint32) error
+
+	// Cancel the controller command that is in progress. Answers false if there was no command to cancel.
+	CancelControllerCommand(homeId uint32) (bool, error)
+
+	// Get the node id of the controller of the network.
+	GetControllerNodeId(homeId uint32) (uint8, error)
+
+	// Answer true if the controller is the primary controller of the network.
+	IsPrimaryController(homeId uint32) (bool, error)
+
+	// Answer true if the controller is the static update controller (SUC) of the network.
+	IsStaticUpdateController(homeId uint32) (bool, error)
+
+	// Write the zwcfg XML file of the network, so that recent changes survive an unclean exit.
+	WriteConfig(homeId uint32) error
+
+	// Get the number of messages waiting to be sent to the network.
+	GetSendQueueCount(homeId uint32) (int32, error)
+
+	// Reset the controller without erasing its network configuration.
+	SoftReset(homeId uint32) error
+
+	// Reset the controller to its factory defaults, erasing the network. confirm must be true.
+	HardReset(homeId uint32, confirm bool) error
+
+	// Get the message statistics of the driver of a network.
+	GetDriverStatistics(homeId uint32) (DriverStats, error)
+
+	// Get the message statistics of a node.
+	GetNodeStatistics(homeId uint32, nodeId uint8) (NodeStats, error)
+
+	// Get the name of a node
+	GetNodeName(homeId uint32, nodeId uint8) (string, error)
+
+	// Set the name of a node. The name is persisted in the zwcfg XML file.
+	SetNodeName(homeId uint32, nodeId uint8, name string) error
+
+	// Get the location of a node
+	GetNodeLocation(homeId uint32, nodeId uint8) (string, error)
+
+	// Set the location of a node. The location is persisted in the zwcfg XML file.
+	SetNodeLocation(homeId uint32, nodeId uint8, location string) error
+
+	// Get the manufacturer name of a node. Empty until the node's queries are complete.
+	GetNodeManufacturerName(homeId uint32, nodeId uint8) (string, error)
+
+	// Get the product name of a node. Empty until the node's queries are complete.
+	GetNodeProductName(homeId uint32, nodeId uint8) (string, error)
+
+	// Get the product type of a node. Empty until the node's queries are complete.
+	GetNodeProductType(homeId uint32, nodeId uint8) (string, error)
+
+	// Get the product id of a node. Empty until the node's queries are complete.
+	GetNodeProductId(homeId uint32, nodeId uint8) (string, error)
+
+	// Force the node to be re-interviewed
+	RefreshNodeInfo(homeId uint32, nodeId uint8) error
+
+	// Get the name of the query stage of a node
+	GetNodeQueryStage(homeId uint32, nodeId uint8) (string, error)
+
+	// Answer true if the node information frame of the node has been received
+	IsNodeInfoReceived(homeId uint32, nodeId uint8) (bool, error)
+
+	// Request the values of all the command classes of a node. Answers true if the requests were queued.
+	RequestNodeState(homeId uint32, nodeId uint8) (bool, error)
+
+	// Request the dynamic values of a node. Answers true if the requests were queued.
+	RequestNodeDynamic(homeId uint32, nodeId uint8) (bool, error)
+
+	// Get the battery level of a node. ok is false if the node has no battery level value.
+	GetBatteryLevel(homeId uint32, nodeId uint8) (uint8, bool, error)
+
+	// Set the interval, in seconds, at which a sleeping node wakes up.
+	SetWakeupInterval(homeId uint32, nodeId uint8, seconds uint32) error
+
+	// Set the level of a node with the Basic command class.
+	SetBasic(homeId uint32, nodeId uint8, level uint8) error
+
+	// Get the level of a node from the Basic command class. ok is false if the node has no basic value.
+	GetBasic(homeId uint32, nodeId uint8) (uint8, bool, error)
+
+	// Get the ids of the neighbors of a node.
+	GetNodeNeighbors(homeId uint32, nodeId uint8) ([]uint8, error)
+
+	// Answer true if the node is always listening, that is, it is mains powered.
+	IsNodeListeningDevice(homeId uint32, nodeId uint8) (bool, error)
+
+	// Answer true if the node is a frequently listening (FLiRS) device, which wakes up when beamed.
+	IsNodeFrequentListeningDevice(homeId uint32, nodeId uint8) (bool, error)
+
+	// Answer true if the node supports beaming, so that it can wake FLiRS devices.
+	IsNodeBeamingDevice(homeId uint32, nodeId uint8) (bool, error)
+
+	// Answer true if the node routes messages for other nodes.
+	IsNodeRoutingDevice(homeId uint32, nodeId uint8) (bool, error)
+
+	// Answer true if the node supports secure communication.
+	IsNodeSecurityDevice(homeId uint32, nodeId uint8) (bool, error)
+
+	// Get the basic device class of a node.
+	GetNodeBasicType(homeId uint32, nodeId uint8) (uint8, error)
+
+	// Get the generic device class of a node.
+	GetNodeGenericType(homeId uint32, nodeId uint8) (uint8, error)
+
+	// Get the specific device class of a node, which refines its generic device class.
+	GetNodeSpecificType(homeId uint32, nodeId uint8) (uint8, error)
+
+	// Get the name of the device class of a node, such as "Multilevel Power Switch".
+	GetNodeType(homeId uint32, nodeId uint8) (string, error)
+
+	// Answer true if the node is awake. Transitions are reported with CODE.AWAKE and CODE.SLEEP.
+	IsNodeAwake(homeId uint32, nodeId uint8) (bool, error)
+
+	// Get the ids of the values of a node, in ascending order.
+	GetNodeValueIDs(homeId uint32, nodeId uint8) ([]uint64, error)
+
+	// Get the ids of the values of one instance of a command class of a node, in ascending order.
+	GetValuesByInstance(homeId uint32, nodeId uint8, commandClassId uint8, instance uint8) ([]uint64, error)
+
+	// Get the number of association groups supported by a node. Groups are numbered from 1.
+	GetNumGroups(homeId uint32, nodeId uint8) (uint8, error)
+
+	// Get the label of an association group of a node.
+	GetGroupLabel(homeId uint32, nodeId uint8, group uint8) (string, error)
+
+	// Get the ids of the nodes that are members of an association group of a node.
+	GetAssociations(homeId uint32, nodeId uint8, group uint8) ([]uint8, error)
+
+	// Add a node to an association group of a node.
+	AddAssociation(homeId uint32, nodeId uint8, group uint8, target uint8) error
+
+	// Remove a node from an association group of a node.
+	RemoveAssociation(homeId uint32, nodeId uint8, group uint8, target uint8) error
+
+	// Set the value of a configuration parameter of a device. size must be 1, 2 or 4.
+	SetConfigParam(homeId uint32, nodeId uint8, param uint8, value int32, size uint8) (bool, error)
+
+	// Request the value of a configuration parameter of a device.
+	RequestConfigParam(homeId uint32, nodeId uint8, param uint8) error
+
+	// Request the values of all the configuration parameters of a device.
+	RequestAllConfigParams(homeId uint32, nodeId uint8) error
+
+	// Create a new scene, answering its id.
+	CreateScene() (uint8, error)
+
+	// Remove a scene.
+	RemoveScene(sceneId uint8) error
+
+	// Get the ids of all the scenes.
+	GetScenes() ([]uint8, error)
+
+	// Set the label of a scene.
+	SetSceneLabel(sceneId uint8, label string) error
+
+	// Add a value to a scene, with the value it is to be set to when the scene is activated.
+	AddSceneBoolValue(sceneId uint8, homeId uint32, valueId uint64, value bool) error
+	AddSceneIntValue(sceneId uint8, homeId uint32, valueId uint64, value int32) error
+	AddSceneByteValue(sceneId uint8, homeId uint32, valueId uint64, value uint8) error
+	AddSceneStringValue(sceneId uint8, homeId uint32, valueId uint64, value string) error
+
+	// Remove a value from a scene.
+	RemoveSceneValue(sceneId uint8, homeId uint32, valueId uint64) error
+
+	// Set each of the values of a scene to the value recorded in the scene.
+	ActivateScene(sceneId uint8) error
+
+	// Enable polling of a value, reading it every intensity poll cycles.
+	EnablePoll(homeId uint32, valueId uint64, intensity uint8) (bool, error)
+
+	// Disable polling of a value.
+	DisablePoll(homeId uint32, valueId uint64) (bool, error)
+
+	// Set the time period between polls.
+	SetPollInterval(milliseconds int32, intervalBetweenPolls bool) error
+
+	// Answer true if the value is being polled.
+	IsPolled(homeId uint32, valueId uint64) (bool, error)
 }
 
 //
@@ -65,21 +438,36 @@ func BuildAPI(configPath string, userPath string, overrides string) Configurator
 		cUserPath   *C.char = C.CString(userPath)
 		cOverrides  *C.char = C.CString(overrides)
 	)
-	//defer C.free(unsafe.Pointer(cConfigPath))
-	//defer C.free(unsafe.Pointer(cUserPath))
-	//defer C.free(unsafe.Pointer(cOverrides)
+	// the C++ Options object copies the strings, so they can be freed when we are done
+	defer C.free(unsafe.Pointer(cConfigPath))
+	defer C.free(unsafe.Pointer(cUserPath))
+	defer C.free(unsafe.Pointer(cOverrides))
 	C.startOptions(cConfigPath, cUserPath, cOverrides)
 	return &api{
-		loop:              defaultEventLoop,
-		callback:          nil,
-		eventCallback:     defaultEventCallback,
-		deviceFactory:     defaultDeviceFactory,
-		device:            defaultDriverName,
-		quitEventLoop:     make(chan int, 0),
-		shutdownDriver:    make(chan int, 2),
-		logger:            &defaultLogger{},
-		networks:          make(map[uint32]*network),
-		quitDeviceMonitor: make(chan int, 2)}
+		loop:               defaultEventLoop,
+		configPath:         configPath,
+		userPath:           userPath,
+		callback:           nil,
+		eventCallback:      defaultEventCallback,
+		deviceFactory:      defaultDeviceFactory,
+		device:             defaultDriverName,
+		quitEventLoop:      make(chan int, 0),
+		shutdownDriver:     make(chan int, 2),
+		logger:             &defaultLogger{},
+		networks:           make(map[uint32]*network),
+		quitDeviceMonitor:  make(chan int, 2),
+		managerStopped:     make(chan struct{}),
+		valueCache:         valueCache{values: make(map[cacheKey]interface{})},
+		controllerCallback: defaultControllerCallback}
+}
+
+//
+// Answer the version of the OpenZWave library the package is linked against, as
+// MAJOR.MINOR.REVISION. This does not require the manager to be started, so it can
+// be logged before Run is called.
+//
+func LibraryVersion() string {
+	return takeString(C.getVersionAsString())
 }
 
 func (a *api) QuitSignal() chan int {
@@ -90,7 +478,19 @@ func (a *api) Logger() Logger {
 	return a.logger
 }
 
+// the name of the device the API is bound to
+func (a *api) DeviceName() string {
+	return a.device
+}
+
+// the names of all the devices the API is bound to, starting with the primary device
+func (a *api) DeviceNames() []string {
+	return append([]string{a.device}, a.additionalDevices...)
+}
+
 func (a *api) getNetwork(homeId uint32) *network {
+	a.model.Lock()
+	defer a.model.Unlock()
 	net, ok := a.networks[homeId]
 	if !ok {
 		net = newNetwork(homeId)
@@ -99,6 +499,67 @@ func (a *api) getNetwork(homeId uint32) *network {
 	return net
 }
 
+//
+// Answer true once the initial queries of the network are complete, that is, once the
+// AWAKE_NODES_QUERIED, ALL_NODES_QUERIED or ALL_NODES_QUERIED_SOME_DEAD notification has
+// been received for it. Sleeping nodes may still be incomplete. Answers false for an
+// unknown network, and again after the driver is reset.
+//
+func (a *api) IsNetworkReady(homeId uint32) bool {
+	a.model.RLock()
+	defer a.model.RUnlock()
+	net, ok := a.networks[homeId]
+	return ok && net.ready
+}
+
+//
+// Find the node in the network model, if it is known.
+//
+// The model is changed by the notification thread, which takes the model lock to do so.
+// Other goroutines must hold the read lock while they use the node, and must copy out what
+// they need rather than keep the node once the lock is released.
+//
+func (a *api) lookupNode(homeId uint32, nodeId uint8) (*node, bool) {
+	net, ok := a.networks[homeId]
+	if !ok {
+		return nil, false
+	}
+	n, ok := net.nodes[nodeId]
+	return n, ok
+}
+
+// find the value in the network model, if it is known. The caller must hold the model lock, as for lookupNode.
+func (a *api) lookupValue(homeId uint32, valueId uint64) (*value, bool) {
+	n, ok := a.lookupNode(homeId, nodeIdOf(valueId))
+	if !ok {
+		return nil, false
+	}
+	v, ok := n.GetValue(commandClassIdOf(valueId), instanceOf(valueId), indexOf(valueId)).(*value)
+	return v, ok
+}
+
+// answer true if the node is known in the network model
+func (a *api) isNodeKnown(homeId uint32, nodeId uint8) bool {
+	a.model.RLock()
+	defer a.model.RUnlock()
+	_, ok := a.lookupNode(homeId, nodeId)
+	return ok
+}
+
+// answer true if the value is known in the network model
+func (a *api) isValueKnown(homeId uint32, valueId uint64) bool {
+	a.model.RLock()
+	defer a.model.RUnlock()
+	_, ok := a.lookupValue(homeId, valueId)
+	return ok
+}
+
+// convert a C string allocated by the C layer into a Go string, then free the C string
+func takeString(cString *C.char) string {
+	defer C.free(unsafe.Pointer(cString))
+	return C.GoString(cString)
+}
+
 func (a *api) notifyEvent(event Event) {
 	if a.eventCallback != nil {
 		a.eventCallback(a, event)
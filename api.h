@@ -45,6 +45,12 @@ typedef void API;
 #include "api/value.h"
 #include "api/notification.h"
 #include "api/options.h"
+#include "api/association.h"
+#include "api/parameter.h"
+#include "api/scene.h"
+#include "api/poll.h"
+#include "api/stats.h"
+#include "api/ozwlog.h"
 
 #ifdef __cplusplus
 #include "_cgo_export.h"
@@ -0,0 +1,6 @@
+extern uint8_t getNumGroups(uint32_t homeId, uint8_t nodeId);
+extern char * getGroupLabel(uint32_t homeId, uint8_t nodeId, uint8_t group);
+extern uint32_t getAssociations(uint32_t homeId, uint8_t nodeId, uint8_t group, uint8_t ** associations);
+extern void freeAssociations(uint8_t * associations);
+extern void addAssociation(uint32_t homeId, uint8_t nodeId, uint8_t group, uint8_t target);
+extern void removeAssociation(uint32_t homeId, uint8_t nodeId, uint8_t group, uint8_t target);
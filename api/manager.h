@@ -2,3 +2,19 @@ extern void startManager(API * api);
 extern void stopManager(API * api);
 extern bool addDriver(char * device);
 extern bool removeDriver(char * device);
+extern bool isManagerStarted();
+extern bool beginControllerCommand(API * api, uint32_t homeId, uint8_t command, bool highPower, uint8_t nodeId, uint8_t arg);
+extern bool cancelControllerCommand(uint32_t homeId);
+extern void healNetworkNode(uint32_t homeId, uint8_t nodeId, bool doRR);
+extern void healNetwork(uint32_t homeId, bool doRR);
+extern void testNetworkNode(uint32_t homeId, uint8_t nodeId, uint32_t count);
+extern void testNetwork(uint32_t homeId, uint32_t count);
+extern uint8_t getControllerNodeId(uint32_t homeId);
+extern bool isPrimaryController(uint32_t homeId);
+extern bool isStaticUpdateController(uint32_t homeId);
+extern void writeConfig(uint32_t homeId);
+extern void softReset(uint32_t homeId);
+extern void resetController(uint32_t homeId);
+extern char * getControllerPath(uint32_t homeId);
+extern char * getVersionAsString();
+extern int32_t getSendQueueCount(uint32_t homeId);
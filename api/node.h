@@ -21,6 +21,33 @@ typedef struct Node {
 } Node;
 
 extern void freeNode(Node *);
+extern char * getNodeName(uint32_t homeId, uint8_t nodeId);
+extern void setNodeName(uint32_t homeId, uint8_t nodeId, char * name);
+extern char * getNodeLocation(uint32_t homeId, uint8_t nodeId);
+extern void setNodeLocation(uint32_t homeId, uint8_t nodeId, char * location);
+extern char * getNodeManufacturerName(uint32_t homeId, uint8_t nodeId);
+extern char * getNodeProductName(uint32_t homeId, uint8_t nodeId);
+extern char * getNodeProductType(uint32_t homeId, uint8_t nodeId);
+extern char * getNodeProductId(uint32_t homeId, uint8_t nodeId);
+extern bool isNodeFailed(uint32_t homeId, uint8_t nodeId);
+extern bool refreshNodeInfo(uint32_t homeId, uint8_t nodeId);
+extern char * getNodeQueryStage(uint32_t homeId, uint8_t nodeId);
+extern bool isNodeInfoReceived(uint32_t homeId, uint8_t nodeId);
+extern bool requestNodeState(uint32_t homeId, uint8_t nodeId);
+extern bool requestNodeDynamic(uint32_t homeId, uint8_t nodeId);
+extern uint32_t getNodeNeighbors(uint32_t homeId, uint8_t nodeId, uint8_t ** neighbors);
+extern void freeNodeNeighbors(uint8_t * neighbors);
+extern bool isNodeListeningDevice(uint32_t homeId, uint8_t nodeId);
+extern bool isNodeFrequentListeningDevice(uint32_t homeId, uint8_t nodeId);
+extern bool isNodeBeamingDevice(uint32_t homeId, uint8_t nodeId);
+extern bool isNodeRoutingDevice(uint32_t homeId, uint8_t nodeId);
+extern bool isNodeSecurityDevice(uint32_t homeId, uint8_t nodeId);
+extern uint8_t getNodeBasic(uint32_t homeId, uint8_t nodeId);
+extern uint8_t getNodeGeneric(uint32_t homeId, uint8_t nodeId);
+extern uint8_t getNodeSpecific(uint32_t homeId, uint8_t nodeId);
+extern char * getNodeType(uint32_t homeId, uint8_t nodeId);
+extern bool isNodeAwake(uint32_t homeId, uint8_t nodeId);
+
 #ifdef __cplusplus
 extern Node * exportNode(API * api, uint32 homeId, uint8 nodeId);
 #endif
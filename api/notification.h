@@ -3,6 +3,10 @@ typedef void GoNotification;
 typedef struct Notification {
   uint8_t          notificationType;
   uint8_t          notificationCode;
+  uint32_t         homeId;
+  uint8_t          nodeId;
+  uint64_t         valueId;
+  uint8_t          byte; // the group, event, button, scene or notification code, depending on the type
   Node           * node; //owned
   Value          * value; // owned
 } Notification;
@@ -3,3 +3,6 @@ extern void addIntOption(char *, int );
 extern void addBoolOption(char *, bool flag);
 extern void addStringOption(char *, char * value, bool append);
 extern void endOptions();
+extern bool getIntOption(char * option, int * value);
+extern bool getBoolOption(char * option, bool * value);
+extern bool getStringOption(char * option, char ** value);
@@ -0,0 +1 @@
+extern void captureLog(API * api);
@@ -0,0 +1,3 @@
+extern bool setConfigParam(uint32_t homeId, uint8_t nodeId, uint8_t param, int32_t value, uint8_t size);
+extern void requestConfigParam(uint32_t homeId, uint8_t nodeId, uint8_t param);
+extern void requestAllConfigParams(uint32_t homeId, uint8_t nodeId);
@@ -0,0 +1,4 @@
+extern bool enablePoll(uint32_t homeId, uint64_t id, uint8_t intensity);
+extern bool disablePoll(uint32_t homeId, uint64_t id);
+extern void setPollInterval(int32_t milliseconds, bool intervalBetweenPolls);
+extern bool isPolled(uint32_t homeId, uint64_t id);
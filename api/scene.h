@@ -0,0 +1,12 @@
+extern uint8_t createScene();
+extern bool removeScene(uint8_t sceneId);
+extern uint8_t getAllScenes(uint8_t ** sceneIds);
+extern void freeScenes(uint8_t * sceneIds);
+extern bool sceneExists(uint8_t sceneId);
+extern void setSceneLabel(uint8_t sceneId, char * label);
+extern bool addSceneBoolValue(uint8_t sceneId, uint32_t homeId, uint64_t id, bool value);
+extern bool addSceneIntValue(uint8_t sceneId, uint32_t homeId, uint64_t id, int32_t value);
+extern bool addSceneUint8Value(uint8_t sceneId, uint32_t homeId, uint64_t id, uint8_t value);
+extern bool addSceneStringValue(uint8_t sceneId, uint32_t homeId, uint64_t id, char * value);
+extern bool removeSceneValue(uint8_t sceneId, uint32_t homeId, uint64_t id);
+extern bool activateScene(uint8_t sceneId);
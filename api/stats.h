@@ -0,0 +1,40 @@
+typedef struct NodeStats {
+  uint32_t sentCount;
+  uint32_t sentFailed;
+  uint32_t retryCount;
+  uint32_t receivedCount;
+  uint32_t receivedDuplicates;
+  uint32_t receivedUnsolicited;
+  uint32_t lastRequestRTT;
+  uint32_t averageRequestRTT;
+  uint32_t lastResponseRTT;
+  uint32_t averageResponseRTT;
+  uint8_t  quality;
+} NodeStats;
+
+typedef struct DriverStats {
+  uint32_t sofCount;
+  uint32_t ackWaiting;
+  uint32_t readAborts;
+  uint32_t badChecksums;
+  uint32_t readCount;
+  uint32_t writeCount;
+  uint32_t canCount;
+  uint32_t nakCount;
+  uint32_t ackCount;
+  uint32_t oofCount;
+  uint32_t dropped;
+  uint32_t retries;
+  uint32_t callbacks;
+  uint32_t badRoutes;
+  uint32_t noAck;
+  uint32_t netBusy;
+  uint32_t notIdle;
+  uint32_t nonDelivery;
+  uint32_t routedBusy;
+  uint32_t broadcastReadCount;
+  uint32_t broadcastWriteCount;
+} DriverStats;
+
+extern void getNodeStatistics(uint32_t homeId, uint8_t nodeId, NodeStats * stats);
+extern void getDriverStatistics(uint32_t homeId, DriverStats * stats);
@@ -30,10 +30,27 @@ extern bool  setFloatValue(uint32_t homeId, uint64_t id, float value);
 extern bool  getFloatValue(uint32_t homeId, uint64_t id, float *value);
 extern bool  setIntValue(uint32_t homeId, uint64_t id, int value);
 extern bool  getIntValue(uint32_t homeId, uint64_t id, int *value);
+extern bool  setShortValue(uint32_t homeId, uint64_t id, int16_t value);
+extern bool  getShortValue(uint32_t homeId, uint64_t id, int16_t *value);
 extern bool  setStringValue(uint32_t homeId, uint64_t id, char * value);
 extern bool  getStringValue(uint32_t homeId, uint64_t id, char ** value);
+extern bool  getValueFloatPrecision(uint32_t homeId, uint64_t id, uint8_t *precision);
+extern bool  setRawValue(uint32_t homeId, uint64_t id, uint8_t * value, uint8_t length);
+extern bool  getRawValue(uint32_t homeId, uint64_t id, uint8_t ** value, uint8_t * length);
+extern void  freeRawValue(uint8_t * value);
 extern bool  refreshValue(uint32_t homeId, uint64_t id);
 extern bool  setPollingState(uint32_t homeId, uint64_t id, bool state);
+extern char *getValueLabel(uint32_t homeId, uint64_t id);
+extern char *getValueHelp(uint32_t homeId, uint64_t id);
+extern char *getValueUnits(uint32_t homeId, uint64_t id);
+extern int32_t getValueMin(uint32_t homeId, uint64_t id);
+extern int32_t getValueMax(uint32_t homeId, uint64_t id);
+extern bool  isValueReadOnly(uint32_t homeId, uint64_t id);
+extern bool  isValueWriteOnly(uint32_t homeId, uint64_t id);
+extern bool  getValueListItems(uint32_t homeId, uint64_t id, char *** items, int * count);
+extern void  freeValueListItems(char ** items, int count);
+extern bool  getValueListSelection(uint32_t homeId, uint64_t id, char ** value);
+extern bool  setValueListSelection(uint32_t homeId, uint64_t id, char * item);
 
 #ifdef __cplusplus
 extern Value * exportValue(API *, uint32_t homeId, OpenZWave::ValueID const &);
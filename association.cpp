@@ -0,0 +1,33 @@
+#include "api.h"
+
+uint8_t getNumGroups(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->GetNumGroups(homeId, nodeId);
+}
+
+char * getGroupLabel(uint32_t homeId, uint8_t nodeId, uint8_t group)
+{
+  return strdup(OpenZWave::Manager::Get()->GetGroupLabel(homeId, nodeId, group).c_str());
+}
+
+// exports the members of an association group into an array that must be released with freeAssociations
+uint32_t getAssociations(uint32_t homeId, uint8_t nodeId, uint8_t group, uint8_t ** associations)
+{
+  *associations = NULL;
+  return OpenZWave::Manager::Get()->GetAssociations(homeId, nodeId, group, associations);
+}
+
+void freeAssociations(uint8_t * associations)
+{
+  delete [] associations;
+}
+
+void addAssociation(uint32_t homeId, uint8_t nodeId, uint8_t group, uint8_t target)
+{
+  OpenZWave::Manager::Get()->AddAssociation(homeId, nodeId, group, target);
+}
+
+void removeAssociation(uint32_t homeId, uint8_t nodeId, uint8_t group, uint8_t target)
+{
+  OpenZWave::Manager::Get()->RemoveAssociation(homeId, nodeId, group, target);
+}
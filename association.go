@@ -0,0 +1,69 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+import "unsafe"
+
+//
+// Association groups are numbered from 1, so if GetNumGroups answers 4, the valid
+// group numbers for a node are 1 to 4.
+//
+
+// get the number of association groups supported by a node
+func (a *api) GetNumGroups(homeId uint32, nodeId uint8) (uint8, error) {
+	if err := checkManager(); err != nil {
+		return 0, err
+	}
+	return (uint8)(C.getNumGroups(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// get the label of an association group of a node, for example "Lifeline"
+func (a *api) GetGroupLabel(homeId uint32, nodeId uint8, group uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getGroupLabel(C.uint32_t(homeId), C.uint8_t(nodeId), C.uint8_t(group))), nil
+}
+
+// get the ids of the nodes that are members of an association group of a node
+func (a *api) GetAssociations(homeId uint32, nodeId uint8, group uint8) ([]uint8, error) {
+	if err := checkManager(); err != nil {
+		return nil, err
+	}
+	var associations *C.uint8_t
+	count := C.getAssociations(C.uint32_t(homeId), C.uint8_t(nodeId), C.uint8_t(group), &associations)
+	defer C.freeAssociations(associations)
+
+	result := make([]uint8, int(count))
+	for i, target := range unsafe.Slice(associations, int(count)) {
+		result[i] = (uint8)(target)
+	}
+	return result, nil
+}
+
+//
+// Add a node to an association group of a node.
+//
+// The request is sent to the device asynchronously - a GROUP notification is generated
+// once the device has reported the updated membership of the group.
+//
+func (a *api) AddAssociation(homeId uint32, nodeId uint8, group uint8, target uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	C.addAssociation(C.uint32_t(homeId), C.uint8_t(nodeId), C.uint8_t(group), C.uint8_t(target))
+	return nil
+}
+
+// remove a node from an association group of a node. Like AddAssociation, the request is asynchronous.
+func (a *api) RemoveAssociation(homeId uint32, nodeId uint8, group uint8, target uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	C.removeAssociation(C.uint32_t(homeId), C.uint8_t(nodeId), C.uint8_t(group), C.uint8_t(target))
+	return nil
+}
@@ -0,0 +1,48 @@
+package openzwave
+
+//
+// A Builder collects the paths that are passed to BuildAPI, so that the construction of
+// the API names each path rather than relying on the order of three strings. For example:
+//
+//	os.Exit(openzwave.NewBuilder().
+//		SetConfigPath("/usr/local/etc/openzwave").
+//		SetUserPath("/var/lib/openzwave").
+//		Build().
+//		SetDeviceName("/dev/ttyUSB0").
+//		Run())
+//
+// The zero Builder is ready to use, and is equivalent to BuildAPI("", "", "").
+//
+type Builder struct {
+	configPath string
+	userPath   string
+	overrides  string
+}
+
+// begin the construction of the API with a Builder
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// set the directory containing the openzwave configuration files
+func (b *Builder) SetConfigPath(configPath string) *Builder {
+	b.configPath = configPath
+	return b
+}
+
+// set the directory containing the user specific openzwave configuration files
+func (b *Builder) SetUserPath(userPath string) *Builder {
+	b.userPath = userPath
+	return b
+}
+
+// set the command line options that override the configuration files
+func (b *Builder) SetOverrides(overrides string) *Builder {
+	b.overrides = overrides
+	return b
+}
+
+// finish the construction of the paths, continuing with a Configurator
+func (b *Builder) Build() Configurator {
+	return BuildAPI(b.configPath, b.userPath, b.overrides)
+}
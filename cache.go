@@ -0,0 +1,123 @@
+package openzwave
+
+import (
+	"sync"
+
+	"github.com/ninjasphere/go-openzwave/NT"
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+type cacheKey struct {
+	homeId  uint32
+	valueId uint64
+}
+
+type valueCache struct {
+	sync.RWMutex
+	enabled bool
+	values  map[cacheKey]interface{}
+}
+
+//
+// Enable or disable the value cache.
+//
+// When the cache is enabled, the value reported by each VALUE_CHANGED notification is
+// recorded, so that CachedValue can answer the latest known state of a value without
+// querying the device. Entries are removed when the value, its node or its network
+// is removed.
+//
+func (a *api) SetValueCache(enabled bool) Configurator {
+	a.valueCache.enabled = enabled
+	return a
+}
+
+//
+// Answer the value recorded by the value cache from the last VALUE_CHANGED notification
+// for the value. ok is false if the cache is disabled or no change has been reported yet.
+//
+// The type of the result depends on the type of the value: bool, uint8, int16, int32,
+// float64 (for decimal values), string (for string and list values) or []byte (for raw values).
+//
+func (a *api) CachedValue(homeId uint32, valueId uint64) (interface{}, bool) {
+	a.valueCache.RLock()
+	defer a.valueCache.RUnlock()
+	value, ok := a.valueCache.values[cacheKey{homeId, valueId}]
+	return value, ok
+}
+
+// record or invalidate the values affected by the notification
+func (a *api) updateValueCache(n *notification) {
+	if !a.valueCache.enabled {
+		return
+	}
+
+	homeId := n.GetHomeId()
+	switch n.GetNotificationType().Code {
+	case NT.VALUE_CHANGED:
+		valueId := n.GetValueId()
+		value, ok := a.readValue(homeId, valueId)
+		a.valueCache.Lock()
+		defer a.valueCache.Unlock()
+		if ok {
+			a.valueCache.values[cacheKey{homeId, valueId}] = value
+		} else {
+			delete(a.valueCache.values, cacheKey{homeId, valueId})
+		}
+	case NT.VALUE_REMOVED:
+		a.valueCache.Lock()
+		defer a.valueCache.Unlock()
+		delete(a.valueCache.values, cacheKey{homeId, n.GetValueId()})
+	case NT.NODE_REMOVED:
+		a.invalidateValueCache(func(key cacheKey) bool {
+			return key.homeId == homeId && nodeIdOf(key.valueId) == n.GetNodeId()
+		})
+	case NT.DRIVER_READY, NT.DRIVER_RESET:
+		a.invalidateValueCache(func(key cacheKey) bool {
+			return key.homeId == homeId
+		})
+	}
+}
+
+// remove the cached values selected by the predicate
+func (a *api) invalidateValueCache(selected func(key cacheKey) bool) {
+	a.valueCache.Lock()
+	defer a.valueCache.Unlock()
+	for key := range a.valueCache.values {
+		if selected(key) {
+			delete(a.valueCache.values, key)
+		}
+	}
+}
+
+// read the current value with the getter that matches its type
+func (a *api) readValue(homeId uint32, valueId uint64) (interface{}, bool) {
+	var value interface{}
+	var ok bool
+	var err error
+
+	switch valueTypeOf(valueId).Code {
+	case VT.BOOL:
+		value, ok, err = a.GetBoolValue(homeId, valueId)
+	case VT.BYTE:
+		value, ok, err = a.GetByteValue(homeId, valueId)
+	case VT.SHORT:
+		var short int16
+		short, ok, err = a.getShortValue(homeId, valueId)
+		value = short
+	case VT.INT:
+		value, ok, err = a.GetIntValue(homeId, valueId)
+	case VT.DECIMAL:
+		value, ok, err = a.GetValueAsFloat(homeId, valueId)
+	case VT.STRING:
+		value, ok, err = a.GetStringValue(homeId, valueId)
+	case VT.LIST:
+		value, err = a.GetValueListSelection(homeId, valueId)
+		ok = err == nil
+	case VT.RAW:
+		value, err = a.GetValueAsRaw(homeId, valueId)
+		ok = err == nil
+	default:
+		return nil, false
+	}
+	return value, ok && err == nil
+}
@@ -5,6 +5,15 @@ package openzwave
 // #include "api.h"
 import "C"
 
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unsafe"
+)
+
 // This interface is used to configure the API by setting various options,
 // and the controller device name. When configuration is finished, call the Run method
 // with an EventLoop function.
@@ -15,7 +24,8 @@ import "C"
 //
 //
 type Configurator interface {
-	//Configure the logger implementation.
+	//Configure the logger implementation. All of the API's messages are written to this logger.
+	//A nil logger restores the default, which writes to the standard log package.
 	SetLogger(Logger) Configurator
 
 	//Configure the synchronous notification callback.
@@ -27,6 +37,27 @@ type Configurator interface {
 	//Configure the synchronous events callback
 	SetDeviceFactory(deviceFactory DeviceFactory) Configurator
 
+	//Configure the synchronous controller command progress callback
+	SetControllerCallback(callback ControllerCallback) Configurator
+
+	//Configure the capacity of the channels returned by Subscribe
+	SetNotificationBuffer(size int) Configurator
+
+	//Configure what happens when a notification is delivered to a full subscription channel
+	SetNotificationOverflowPolicy(policy OverflowPolicy) Configurator
+
+	//Configure the window in which changes to the values of a command class are coalesced for subscribers
+	SetValueDebounce(commandClassId uint8, window time.Duration) Configurator
+
+	//Configure how long the notifications that are still pending when the manager stops may take to reach the subscribers
+	SetDrainTimeout(timeout time.Duration) Configurator
+
+	//Configure whether the latest value reported for each value is cached, for CachedValue
+	SetValueCache(enabled bool) Configurator
+
+	//Configure a handler that receives OpenZWave's own log messages, instead of the log file
+	SetOZWLogHandler(handler OZWLogHandler) Configurator
+
 	//Configure the event loop function
 	SetEventLoop(EventLoop) Configurator
 
@@ -39,17 +70,44 @@ type Configurator interface {
 	// Add a string option.
 	AddStringOption(option string, value string, append bool) Configurator
 
+	// Get the value of an integer option. ok is false if there is no such option.
+	GetIntOption(option string) (value int, ok bool)
+
+	// Get the value of a boolean option. ok is false if there is no such option.
+	GetBoolOption(option string) (value bool, ok bool)
+
+	// Get the value of a string option. ok is false if there is no such option.
+	GetStringOption(option string) (value string, ok bool)
+
 	// Set the device name used by the driver.
 	SetDeviceName(device string) Configurator
 
+	// Add the name of an additional device, so that more than one network can be managed.
+	AddDeviceName(device string) Configurator
+
+	// Require the device to exist when Run is called, rather than waiting for it to be inserted.
+	SetDeviceMustExist(mustExist bool) Configurator
+
+	// Run the event loop against a simulated network, instead of OpenZWave and a controller.
+	WithSimulator(config SimConfig) Configurator
+
+	// Check the configuration, answering an error that describes the first problem found
+	Validate() error
+
 	// Run the event loop forever
 	Run() int
+
+	// Run the event loop until the context is cancelled
+	RunContext(ctx context.Context) int
+
+	// Run the event loop forever, returning an error that describes any abnormal exit
+	RunE() (int, error)
 }
 
 // configure the C++ Options object with an integer value
 func (a *api) AddIntOption(option string, value int) Configurator {
 	var cOption *C.char = C.CString(option)
-	//defer C.free(unsafe.Pointer(cOption))
+	defer C.free(unsafe.Pointer(cOption))
 
 	C.addIntOption(cOption, C.int(value))
 	return a
@@ -58,21 +116,65 @@ func (a *api) AddIntOption(option string, value int) Configurator {
 // configure the C++ Options object with a boolean value
 func (a *api) AddBoolOption(option string, value bool) Configurator {
 	var cOption *C.char = C.CString(option)
+	defer C.free(unsafe.Pointer(cOption))
 
-	//defer C.free(unsafe.Pointer(cOption))
 	C.addBoolOption(cOption, C._Bool(value))
 	return a
 }
 
-// configure the C++ Options object with a string value
+// configure the C++ Options object with a string value.
+//
+// The C++ Options object copies both strings, so they are freed as soon as the call returns.
 func (a *api) AddStringOption(option string, value string, append bool) Configurator {
-	var cOption *C.char = C.CString(option)
+	var (
+		cOption *C.char = C.CString(option)
+		cValue  *C.char = C.CString(value)
+	)
+	defer C.free(unsafe.Pointer(cOption))
+	defer C.free(unsafe.Pointer(cValue))
 
-	//defer C.free(unsafe.Pointer(cOption))
-	C.addStringOption(cOption, C.CString(value), C._Bool(append))
+	C.addStringOption(cOption, cValue, C._Bool(append))
 	return a
 }
 
+//
+// Get the value of an integer option from the C++ Options object, as OpenZWave will use it.
+//
+// Options are read from the options.xml files of the config and user paths, and from the
+// command line overrides, only when Run locks the Options object, so until then this answers
+// the defaults of OpenZWave and the values added by AddIntOption.
+//
+func (a *api) GetIntOption(option string) (int, bool) {
+	cOption := C.CString(option)
+	defer C.free(unsafe.Pointer(cOption))
+
+	var value C.int
+	ok := (bool)(C.getIntOption(cOption, &value))
+	return int(value), ok
+}
+
+// get the value of a boolean option from the C++ Options object. See GetIntOption.
+func (a *api) GetBoolOption(option string) (bool, bool) {
+	cOption := C.CString(option)
+	defer C.free(unsafe.Pointer(cOption))
+
+	var value C._Bool
+	ok := (bool)(C.getBoolOption(cOption, &value))
+	return bool(value), ok
+}
+
+// get the value of a string option from the C++ Options object. See GetIntOption.
+func (a *api) GetStringOption(option string) (string, bool) {
+	cOption := C.CString(option)
+	defer C.free(unsafe.Pointer(cOption))
+
+	var value *C.char
+	if !(bool)(C.getStringOption(cOption, &value)) {
+		return "", false
+	}
+	return takeString(value), true
+}
+
 // set the device name
 func (a *api) SetDeviceName(device string) Configurator {
 	if device != "" {
@@ -81,8 +183,146 @@ func (a *api) SetDeviceName(device string) Configurator {
 	return a
 }
 
+//
+// Add the name of an additional device.
+//
+// Each device has its own driver and so its own network, identified by the home id of the
+// notifications received from it. Additional devices may be inserted and removed without
+// stopping the event loop.
+//
+func (a *api) AddDeviceName(device string) Configurator {
+	if device != "" && device != a.device {
+		a.additionalDevices = append(a.additionalDevices, device)
+	}
+	return a
+}
+
+//
+// Set a handler for OpenZWave's own log messages.
+//
+// Once the manager is started, the messages that OpenZWave would have written to the
+// log file and the console are passed to the handler instead. Messages less severe than
+// the SaveLogLevel option are discarded.
+//
+func (a *api) SetOZWLogHandler(handler OZWLogHandler) Configurator {
+	a.ozwLogHandler = handler
+	return a
+}
+
+//
+// Check the configuration.
+//
+// OpenZWave does not report a bad configuration path until it fails to recognise the devices
+// on the network, so this checks that the configuration path passed to BuildAPI is a directory
+// containing the device database, and that the user path, if any, is a directory. It also
+// checks that each device name is an absolute path.
+//
+func (a *api) Validate() error {
+	if a.simulator != nil {
+		// the simulated network needs neither the device database nor a device
+		if newSimulator == nil {
+			return errNoSimulator
+		}
+		return nil
+	}
+	if err := checkDirectory("configuration", a.configPath); err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(a.configPath, "manufacturer_specific.xml")); err != nil {
+		return fmt.Errorf("the configuration path %q does not contain the OpenZWave device database: %v", a.configPath, err)
+	}
+	if a.userPath != "" {
+		if err := checkDirectory("user", a.userPath); err != nil {
+			return err
+		}
+	}
+	for _, device := range a.DeviceNames() {
+		if !filepath.IsAbs(device) {
+			return fmt.Errorf("invalid device name %q: not an absolute path", device)
+		}
+	}
+	if a.deviceMustExist && !deviceExists(a.device) {
+		return fmt.Errorf("the device %s does not exist", a.device)
+	}
+	return nil
+}
+
+// answer an error if the path is not a directory
+func checkDirectory(description string, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("invalid %s path: %v", description, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("invalid %s path: %q is not a directory", description, path)
+	}
+	return nil
+}
+
+//
+// Require the device to exist when Run is called.
+//
+// By default, Run waits for the device to be inserted. If the device must exist, Run instead
+// answers EXIT_NO_DEVICE immediately if it does not, and Validate reports the missing device.
+//
+func (a *api) SetDeviceMustExist(mustExist bool) Configurator {
+	a.deviceMustExist = mustExist
+	return a
+}
+
+//
+// Set the capacity of the channels returned by Subscribe. The default is 0, that is, unbuffered.
+//
+// Notifications are delivered to subscribers from OpenZWave's notification thread, which
+// cannot process further notifications until every matching subscriber has accepted the
+// current one. A buffer allows a subscriber to fall behind briefly - for example, while a
+// large network is being queried at startup - without stalling OpenZWave. Once a buffer is
+// full, delivery blocks as it would for an unbuffered channel, unless an overflow policy
+// that discards notifications has been set.
+//
+func (a *api) SetNotificationBuffer(size int) Configurator {
+	if size >= 0 {
+		a.notificationBuffer = size
+	}
+	return a
+}
+
+//
+// Set what happens when a notification is delivered to a full subscription channel.
+//
+// The default, OVERFLOW_BLOCK, waits for the subscriber, which stalls OpenZWave and can
+// deadlock the removal of the driver if the subscriber has stopped receiving. The other
+// policies never block: they discard a notification instead, and count it in
+// GetDroppedNotifications. The policy does not apply to the methods that wait for a
+// notification, such as RestartDriver, which never miss the notification they await.
+//
+func (a *api) SetNotificationOverflowPolicy(policy OverflowPolicy) Configurator {
+	a.overflowPolicy = policy
+	return a
+}
+
+//
+// Set how long the notifications that are still pending when the manager stops may take to
+// reach the subscribers.
+//
+// When the manager stops, the notification being delivered may be waiting for a subscriber
+// whose channel is full, and debounced notifications may be waiting for the end of their
+// windows. Within the timeout, the former is delivered and the latter are delivered at once,
+// so that a subscriber that keeps receiving sees the last events before the shutdown. What
+// has not been delivered when the timeout expires is discarded. The default, zero, discards
+// the debounced notifications and does not wait for any subscriber.
+//
+func (a *api) SetDrainTimeout(timeout time.Duration) Configurator {
+	a.drainTimeout = timeout
+	return a
+}
+
 // set the logger
 func (a *api) SetLogger(logger Logger) Configurator {
+	if logger == nil {
+		// revert to the default logger, rather than fail on the first message
+		logger = &defaultLogger{}
+	}
 	a.logger = logger
 	return a
 }
@@ -107,6 +347,9 @@ func (a *api) SetEventLoop(loop EventLoop) Configurator {
 // MUST NOT block; MUST NOT hand the reference to the notification to a goroutine which lives beyond
 // the callback call; MUST NOT store the reference to the notification in a structure that lives beyond
 // the duration of the callback call.
+//
+// A panic in the callback is recovered and logged, so that it cannot unwind into OpenZWave's
+// notification thread, and the notification is still delivered to the network and the subscribers.
 type NotificationCallback func(API, Notification)
 
 // set the synchronous call back
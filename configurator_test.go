@@ -0,0 +1,64 @@
+package openzwave
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// the number of calls that would leak noticeably if each one leaked its C strings
+const optionCalls = 500000
+
+func TestAddIntOptionDoesNotGrowMemory(t *testing.T) {
+	dir := t.TempDir()
+	configurator := BuildAPI(dir, dir, "")
+
+	// warm up, so that the option and any caches exist before the baseline is taken
+	for i := 0; i < 1000; i++ {
+		configurator.AddIntOption("PollInterval", i)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	residentBefore := residentSize(t)
+	for i := 0; i < optionCalls; i++ {
+		configurator.AddIntOption("PollInterval", i)
+	}
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	residentAfter := residentSize(t)
+
+	// allow for some noise, but not for memory that grows with the number of calls
+	const allowance = 4 << 20
+	if after.HeapInuse > before.HeapInuse+allowance {
+		t.Fatalf("heap grew from %d to %d bytes over %d calls of AddIntOption", before.HeapInuse, after.HeapInuse, optionCalls)
+	}
+	if after.Sys > before.Sys+allowance {
+		t.Fatalf("memory obtained from the OS grew from %d to %d bytes over %d calls of AddIntOption", before.Sys, after.Sys, optionCalls)
+	}
+	// the C strings are allocated outside the Go heap, so they only show in the resident size
+	if residentAfter > residentBefore+allowance {
+		t.Fatalf("resident size grew from %d to %d bytes over %d calls of AddIntOption", residentBefore, residentAfter, optionCalls)
+	}
+}
+
+// answer the resident size of the process, or 0 where it is not available
+func residentSize(t *testing.T) uint64 {
+	data, err := ioutil.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		t.Fatalf("unexpected /proc/self/statm: %q", data)
+	}
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		t.Fatalf("unexpected /proc/self/statm: %q", data)
+	}
+	return pages * uint64(os.Getpagesize())
+}
@@ -0,0 +1,331 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/ninjasphere/go-openzwave/CE"
+	"github.com/ninjasphere/go-openzwave/CS"
+)
+
+// the subset of OpenZWave::Driver::ControllerCommand used by the API
+const (
+	controllerCommandAddDevice     = 1
+	controllerCommandRemoveDevice  = 4
+	controllerCommandRemoveFailed  = 5
+	controllerCommandReplaceFailed = 7
+	controllerCommandAssignRoute   = 11
+	controllerCommandDeleteRoutes  = 12
+	controllerCommandSendNodeInfo  = 13
+)
+
+//
+// A type of function that receives the progress of controller commands, such as
+// BeginInclusion and BeginExclusion.
+//
+// This version of OpenZWave does not report controller command progress as notifications,
+// so this callback is the only way to learn that, for example, the controller is waiting
+// for the user to press the button on a device (CS.WAITING), or that the command has
+// completed (CS.COMPLETED) or failed (CS.FAILED, CS.ERROR).
+//
+// In particular, the notifications of this version carry no controller state or error, so
+// there is nothing to expose on the Notification interface. Names for the states and errors
+// are answered by the String methods of the CS and CE enums, for example "CS.WAITING".
+//
+// Like the NotificationCallback, this callback is processed synchronously and MUST NOT block.
+//
+type ControllerCallback func(api API, homeId uint32, state *CS.Enum, err *CE.Enum)
+
+var defaultControllerCallback = func(api API, homeId uint32, state *CS.Enum, err *CE.Enum) {
+	api.Logger().Debugf("controller command progress for network 0x%08x - %v/%v\n", homeId, state, err)
+}
+
+// set the controller callback
+func (a *api) SetControllerCallback(callback ControllerCallback) Configurator {
+	a.controllerCallback = callback
+	return a
+}
+
+// begin a controller command, the progress of which is reported to the controller callback
+func (a *api) beginControllerCommand(homeId uint32, command uint8, nodeId uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if !a.queueControllerCommand(homeId, command, nodeId) {
+		return fmt.Errorf("failed to begin controller command %d in network 0x%08x - another command may be in progress", command, homeId)
+	}
+	return nil
+}
+
+// queue a controller command, answering false if it could not be queued
+func (a *api) queueControllerCommand(homeId uint32, command uint8, nodeId uint8) bool {
+	return (bool)(C.beginControllerCommand(unsafe.Pointer(a), C.uint32_t(homeId), C.uint8_t(command), C._Bool(false), C.uint8_t(nodeId), 0))
+}
+
+//
+// Put the controller into inclusion mode, so that a new device can be added to the network.
+//
+// Secure inclusion is not supported by this version of OpenZWave, so an error is returned if
+// secure is true. This version predates S2 as well as the Security command class, so there is
+// no DSK to supply during inclusion, and a "NetworkKey" option added with AddStringOption is
+// ignored. Secure devices can only be included without security, if they allow it.
+//
+func (a *api) BeginInclusion(homeId uint32, secure bool) error {
+	if secure {
+		return fmt.Errorf("secure inclusion is not supported by this version of OpenZWave")
+	}
+	return a.beginControllerCommand(homeId, controllerCommandAddDevice, 0xff)
+}
+
+//
+// Put the controller into exclusion mode, so that a device can be removed from the network.
+//
+// Like inclusion, exclusion can be cancelled with CancelControllerCommand and its progress is
+// reported to the controller callback.
+//
+func (a *api) BeginExclusion(homeId uint32) error {
+	return a.beginControllerCommand(homeId, controllerCommandRemoveDevice, 0xff)
+}
+
+// answer true if the controller believes the node has failed
+func (a *api) HasNodeFailed(homeId uint32, nodeId uint8) (bool, error) {
+	if err := checkManager(); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeFailed(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+//
+// Remove a failed node from the network.
+//
+// An error is returned if the node has not been marked as failed by the controller. The progress
+// of the removal is reported to the controller callback.
+//
+func (a *api) RemoveFailedNode(homeId uint32, nodeId uint8) error {
+	if err := a.checkNodeFailed(homeId, nodeId); err != nil {
+		return err
+	}
+	return a.beginControllerCommand(homeId, controllerCommandRemoveFailed, nodeId)
+}
+
+// answer an error if the node has not been marked as failed by the controller
+func (a *api) checkNodeFailed(homeId uint32, nodeId uint8) error {
+	failed, err := a.HasNodeFailed(homeId, nodeId)
+	if err != nil {
+		return err
+	}
+	if !failed {
+		return fmt.Errorf("node %d in network 0x%08x has not failed", nodeId, homeId)
+	}
+	return nil
+}
+
+//
+// Replace a failed node with a new device that will take over its node id.
+//
+// An error is returned if the node has not been marked as failed by the controller. Once the
+// command has started, the controller waits for the replacement device to be included - the
+// progress is reported to the controller callback.
+//
+func (a *api) ReplaceFailedNode(homeId uint32, nodeId uint8) error {
+	if err := a.checkNodeFailed(homeId, nodeId); err != nil {
+		return err
+	}
+	return a.beginControllerCommand(homeId, controllerCommandReplaceFailed, nodeId)
+}
+
+//
+// Ask a node to broadcast its node information frame, which can prompt a stubborn device to
+// re-establish its routes.
+//
+// Answers false if the command could not be queued, for example because another controller
+// command is in progress. The progress is reported to the controller callback.
+//
+func (a *api) SendNodeInformation(homeId uint32, nodeId uint8) (bool, error) {
+	if err := checkDriver(homeId); err != nil {
+		return false, err
+	}
+	return a.queueControllerCommand(homeId, controllerCommandSendNodeInfo, nodeId), nil
+}
+
+//
+// Assign the node a return route to the controller, which can help a node that can be
+// reached by the controller, but cannot reliably report to it.
+//
+// Answers false if the command could not be queued. The progress is reported to the
+// controller callback.
+//
+func (a *api) AssignReturnRoute(homeId uint32, nodeId uint8) (bool, error) {
+	if err := checkDriver(homeId); err != nil {
+		return false, err
+	}
+	return a.queueControllerCommand(homeId, controllerCommandAssignRoute, nodeId), nil
+}
+
+// delete all the return routes of a node. Answers false if the command could not be queued.
+func (a *api) DeleteAllReturnRoutes(homeId uint32, nodeId uint8) (bool, error) {
+	if err := checkDriver(homeId); err != nil {
+		return false, err
+	}
+	return a.queueControllerCommand(homeId, controllerCommandDeleteRoutes, nodeId), nil
+}
+
+// heal the routes of a node. If doRR is true, the return routes are also updated.
+func (a *api) HealNetworkNode(homeId uint32, nodeId uint8, doRR bool) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	C.healNetworkNode(C.uint32_t(homeId), C.uint8_t(nodeId), C._Bool(doRR))
+	return nil
+}
+
+// heal the routes of every node in the network. If doRR is true, the return routes are also updated.
+func (a *api) HealNetwork(homeId uint32, doRR bool) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	C.healNetwork(C.uint32_t(homeId), C._Bool(doRR))
+	return nil
+}
+
+//
+// Send count test frames to a node, to check the quality of its link.
+//
+// Each frame that the node acknowledges is reported by a NOTIFICATION notification with the
+// code CODE.NO_OPERATION. Frames that are dropped or retried are counted in the statistics
+// answered by GetNodeStatistics.
+//
+func (a *api) TestNetworkNode(homeId uint32, nodeId uint8, count uint32) error {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return err
+	}
+	C.testNetworkNode(C.uint32_t(homeId), C.uint8_t(nodeId), C.uint32_t(count))
+	return nil
+}
+
+// send count test frames to every node in the network. The outcomes are reported as for TestNetworkNode.
+func (a *api) TestNetwork(homeId uint32, count uint32) error {
+	if err := checkDriver(homeId); err != nil {
+		return err
+	}
+	C.testNetwork(C.uint32_t(homeId), C.uint32_t(count))
+	return nil
+}
+
+//
+// Cancel the controller command that is in progress, for example when the user abandons an
+// inclusion. Answers false if there was no command to cancel.
+//
+// Once the command has been cancelled, CS.CANCEL is reported to the controller callback, so
+// that a user interface can return to its idle state.
+//
+func (a *api) CancelControllerCommand(homeId uint32) (bool, error) {
+	if err := checkDriver(homeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.cancelControllerCommand(C.uint32_t(homeId))), nil
+}
+
+//export onControllerStateWrapper
+func onControllerStateWrapper(homeId C.uint32_t, state C.uint8_t, err C.uint8_t, context unsafe.Pointer) {
+	a := (*api)(context)
+	if a.controllerCallback != nil {
+		a.controllerCallback(a, uint32(homeId), CS.ToEnum(int(state)), CE.ToEnum(int(err)))
+	}
+}
+
+// answer an error if the manager is not started or there is no driver for the network
+func checkDriver(homeId uint32) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if C.getControllerNodeId(C.uint32_t(homeId)) == 0xff {
+		return fmt.Errorf("%w for network 0x%08x", ErrNoDriver, homeId)
+	}
+	return nil
+}
+
+// get the node id of the controller of the network
+func (a *api) GetControllerNodeId(homeId uint32) (uint8, error) {
+	if err := checkDriver(homeId); err != nil {
+		return 0, err
+	}
+	return (uint8)(C.getControllerNodeId(C.uint32_t(homeId))), nil
+}
+
+// answer true if the controller is the primary controller of the network
+func (a *api) IsPrimaryController(homeId uint32) (bool, error) {
+	if err := checkDriver(homeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isPrimaryController(C.uint32_t(homeId))), nil
+}
+
+// answer true if the controller is the static update controller (SUC) of the network
+func (a *api) IsStaticUpdateController(homeId uint32) (bool, error) {
+	if err := checkDriver(homeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isStaticUpdateController(C.uint32_t(homeId))), nil
+}
+
+//
+// Write the zwcfg XML file of the network to the user path, so that changes such as node
+// names and associations survive an unclean exit of the process.
+//
+// OpenZWave also writes this file when the driver is removed. Writing is cheap and is
+// serialized by the driver, so it is safe to call this as often as required.
+//
+func (a *api) WriteConfig(homeId uint32) error {
+	if err := checkDriver(homeId); err != nil {
+		return err
+	}
+	C.writeConfig(C.uint32_t(homeId))
+	return nil
+}
+
+//
+// Get the number of messages waiting to be sent to the network.
+//
+// Bulk operations, such as configuring many nodes, can pause while the queue is deep, rather
+// than flood the network with messages that will time out.
+//
+func (a *api) GetSendQueueCount(homeId uint32) (int32, error) {
+	if err := checkDriver(homeId); err != nil {
+		return 0, err
+	}
+	return (int32)(C.getSendQueueCount(C.uint32_t(homeId))), nil
+}
+
+// reset the controller without erasing its network configuration
+func (a *api) SoftReset(homeId uint32) error {
+	if err := checkDriver(homeId); err != nil {
+		return err
+	}
+	C.softReset(C.uint32_t(homeId))
+	return nil
+}
+
+//
+// Reset the controller to its factory defaults. This erases the network configuration -
+// every node must be included again - so confirm must be true, otherwise an error is
+// returned and nothing is done.
+//
+// The completion of the reset is reported by a DRIVER_RESET notification.
+//
+func (a *api) HardReset(homeId uint32, confirm bool) error {
+	if !confirm {
+		return fmt.Errorf("hard reset of network 0x%08x was not confirmed", homeId)
+	}
+	if err := checkDriver(homeId); err != nil {
+		return err
+	}
+	C.resetController(C.uint32_t(homeId))
+	return nil
+}
@@ -0,0 +1,84 @@
+package openzwave
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ninjasphere/go-openzwave/NT"
+)
+
+type debouncer struct {
+	sync.Mutex
+	windows map[uint8]time.Duration // by command class
+	pending map[cacheKey]Notification
+}
+
+//
+// Debounce the VALUE_CHANGED notifications of the values of a command class.
+//
+// Some devices, such as power meters, report changes far more often than subscribers need
+// them. Once a value of the command class changes, the changes during the following window
+// are coalesced, and only the latest is delivered to the subscribers, when the window ends.
+// A window of zero or less stops the debouncing of the command class.
+//
+// Debouncing applies only to the channels returned by Subscribe. The NotificationCallback
+// still receives every notification, and the network model is always up to date. Since a
+// debounced notification is delivered after the window, it may be received after
+// notifications that OpenZWave sent later.
+//
+func (a *api) SetValueDebounce(commandClassId uint8, window time.Duration) Configurator {
+	a.debouncer.Lock()
+	defer a.debouncer.Unlock()
+	if a.debouncer.windows == nil {
+		a.debouncer.windows = make(map[uint8]time.Duration)
+		a.debouncer.pending = make(map[cacheKey]Notification)
+	}
+	if window > 0 {
+		a.debouncer.windows[commandClassId] = window
+	} else {
+		delete(a.debouncer.windows, commandClassId)
+	}
+	return a
+}
+
+// answer true if the delivery of the notification is deferred until the end of the debounce window of its value
+func (a *api) debounce(n Notification) bool {
+	if n.GetNotificationType().Code != NT.VALUE_CHANGED {
+		return false
+	}
+
+	a.debouncer.Lock()
+	defer a.debouncer.Unlock()
+	window, ok := a.debouncer.windows[commandClassIdOf(n.GetValueId())]
+	if !ok {
+		return false
+	}
+
+	key := cacheKey{n.GetHomeId(), n.GetValueId()}
+	_, waiting := a.debouncer.pending[key]
+	a.debouncer.pending[key] = n
+	if !waiting {
+		time.AfterFunc(window, func() {
+			a.debouncer.Lock()
+			latest, ok := a.debouncer.pending[key]
+			delete(a.debouncer.pending, key)
+			a.debouncer.Unlock()
+			if ok {
+				a.broadcast(latest)
+			}
+		})
+	}
+	return true
+}
+
+// remove and answer the notifications that are waiting for the end of their debounce windows
+func (a *api) takeDebounced() []Notification {
+	a.debouncer.Lock()
+	defer a.debouncer.Unlock()
+	taken := []Notification{}
+	for key, n := range a.debouncer.pending {
+		taken = append(taken, n)
+		delete(a.debouncer.pending, key)
+	}
+	return taken
+}
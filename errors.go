@@ -0,0 +1,41 @@
+package openzwave
+
+import (
+	"errors"
+	"fmt"
+)
+
+//
+// The errors that identify the common failures of the API methods.
+//
+// The errors answered by the methods wrap these with the details of the failure, so they
+// should be tested with errors.Is rather than compared directly. For example:
+//
+//	if _, err := api.IsNodeListeningDevice(homeId, nodeId); errors.Is(err, openzwave.ErrUnknownNode) {
+//		forget(nodeId)
+//	}
+//
+var (
+	// the manager has not been started, or has been stopped
+	ErrManagerNotStarted = errors.New("the manager has not been started")
+
+	// there is no driver for the network, for example because its controller has been removed
+	ErrNoDriver = errors.New("there is no driver")
+
+	// the node is not known in the network
+	ErrUnknownNode = errors.New("unknown node")
+
+	// the scene does not exist
+	ErrUnknownScene = errors.New("unknown scene")
+
+	// the value is not of the type required by the method
+	ErrValueTypeMismatch = errors.New("value type mismatch")
+
+	// the value is not known in the network, or its current value is not available
+	ErrValueNotFound = errors.New("value not found")
+)
+
+// answer the error for a node that is not known in the network
+func unknownNode(homeId uint32, nodeId uint8) error {
+	return fmt.Errorf("%w %d in network 0x%08x", ErrUnknownNode, nodeId, homeId)
+}
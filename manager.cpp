@@ -28,3 +28,126 @@ bool removeDriver(char * device)
 {
   return OpenZWave::Manager::Get()->RemoveDriver(device);
 }
+
+bool isManagerStarted()
+{
+  return OpenZWave::Manager::Get() != NULL;
+}
+
+// the context of a controller command - released when the command is done
+typedef struct ControllerContext {
+  API * api;
+  uint32_t homeId;
+} ControllerContext;
+
+// forwards the progress of a controller command from the C++ API to the Go layer
+static void OnControllerState(OpenZWave::Driver::ControllerState state, OpenZWave::Driver::ControllerError error, void * context)
+{
+  ControllerContext * controllerContext = (ControllerContext *)context;
+  onControllerStateWrapper(controllerContext->homeId, state, error, controllerContext->api);
+  switch (state) {
+  // Sleeping is not terminal: OpenZWave keeps the context with the command for a sleeping
+  // node, and begins the command again with it when the node wakes up
+  case OpenZWave::Driver::ControllerState_Error:
+  case OpenZWave::Driver::ControllerState_Cancel:
+  case OpenZWave::Driver::ControllerState_Failed:
+  case OpenZWave::Driver::ControllerState_NodeFailed:
+  case OpenZWave::Driver::ControllerState_NodeOK:
+  case OpenZWave::Driver::ControllerState_Completed:
+    free(controllerContext);
+    break;
+  default:
+    break;
+  }
+}
+
+bool beginControllerCommand(API * api, uint32_t homeId, uint8_t command, bool highPower, uint8_t nodeId, uint8_t arg)
+{
+  ControllerContext * context = (ControllerContext *)malloc(sizeof(ControllerContext));
+  context->api = api;
+  context->homeId = homeId;
+  bool result = OpenZWave::Manager::Get()->BeginControllerCommand(
+    homeId,
+    (OpenZWave::Driver::ControllerCommand)command,
+    OnControllerState,
+    context,
+    highPower,
+    nodeId,
+    arg);
+  if (!result) {
+    free(context);
+  }
+  return result;
+}
+
+bool cancelControllerCommand(uint32_t homeId)
+{
+  return OpenZWave::Manager::Get()->CancelControllerCommand(homeId);
+}
+
+void healNetworkNode(uint32_t homeId, uint8_t nodeId, bool doRR)
+{
+  OpenZWave::Manager::Get()->HealNetworkNode(homeId, nodeId, doRR);
+}
+
+void healNetwork(uint32_t homeId, bool doRR)
+{
+  OpenZWave::Manager::Get()->HealNetwork(homeId, doRR);
+}
+
+void testNetworkNode(uint32_t homeId, uint8_t nodeId, uint32_t count)
+{
+  OpenZWave::Manager::Get()->TestNetworkNode(homeId, nodeId, count);
+}
+
+void testNetwork(uint32_t homeId, uint32_t count)
+{
+  OpenZWave::Manager::Get()->TestNetwork(homeId, count);
+}
+
+// answers 0xff if there is no driver for the network
+uint8_t getControllerNodeId(uint32_t homeId)
+{
+  return OpenZWave::Manager::Get()->GetControllerNodeId(homeId);
+}
+
+bool isPrimaryController(uint32_t homeId)
+{
+  return OpenZWave::Manager::Get()->IsPrimaryController(homeId);
+}
+
+bool isStaticUpdateController(uint32_t homeId)
+{
+  return OpenZWave::Manager::Get()->IsStaticUpdateController(homeId);
+}
+
+void writeConfig(uint32_t homeId)
+{
+  OpenZWave::Manager::Get()->WriteConfig(homeId);
+}
+
+void softReset(uint32_t homeId)
+{
+  OpenZWave::Manager::Get()->SoftReset(homeId);
+}
+
+void resetController(uint32_t homeId)
+{
+  OpenZWave::Manager::Get()->ResetController(homeId);
+}
+
+// answers an empty string if there is no driver for the network
+char * getControllerPath(uint32_t homeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetControllerPath(homeId).c_str());
+}
+
+char * getVersionAsString()
+{
+  return strdup(OpenZWave::Manager::getVersionAsString().c_str());
+}
+
+int32_t getSendQueueCount(uint32_t homeId)
+{
+  return OpenZWave::Manager::Get()->GetSendQueueCount(homeId);
+}
@@ -0,0 +1,100 @@
+//
+// Package metrics provides a Prometheus collector for the message statistics
+// and battery levels of the nodes known to an openzwave.API.
+//
+// The collector is registered in the usual way, for example:
+//
+//	prometheus.MustRegister(metrics.NewCollector(api))
+//
+// Statistics are read from the API each time the collector is scraped. Nothing is
+// reported until the manager has been started and the nodes have been discovered.
+//
+// Unlike the rest of the repository, this package depends on the Prometheus client, which
+// must be fetched into the workspace before the package is built, for example with
+// go get github.com/prometheus/client_golang/prometheus. Programs that do not import the
+// package do not need it.
+//
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/ninjasphere/go-openzwave"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "openzwave"
+
+var labels = []string{"home_id", "node_id"}
+
+// A prometheus.Collector that reports the statistics of each node known to the API.
+type Collector struct {
+	api openzwave.API
+
+	sent     *prometheus.Desc
+	failed   *prometheus.Desc
+	retries  *prometheus.Desc
+	received *prometheus.Desc
+	rtt      *prometheus.Desc
+	battery  *prometheus.Desc
+	dropped  *prometheus.Desc
+}
+
+// create a collector for the nodes known to the API
+func NewCollector(api openzwave.API) *Collector {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "node", name), help, labels, nil)
+	}
+	return &Collector{
+		api:      api,
+		sent:     desc("sent_total", "The number of messages sent to the node."),
+		failed:   desc("sent_failed_total", "The number of messages that could not be sent to the node."),
+		retries:  desc("retries_total", "The number of messages to the node that were retried."),
+		received: desc("received_total", "The number of messages received from the node."),
+		rtt:      desc("average_request_rtt_milliseconds", "The average round trip time of requests to the node."),
+		battery:  desc("battery_level_percent", "The battery level of the node."),
+		dropped: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "dropped_notifications_total"),
+			"The number of notifications discarded because a subscriber's channel was full.", nil, nil),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sent
+	ch <- c.failed
+	ch <- c.retries
+	ch <- c.received
+	ch <- c.rtt
+	ch <- c.battery
+	ch <- c.dropped
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(c.api.GetDroppedNotifications()))
+
+	snapshot, err := c.api.Snapshot()
+	if err != nil {
+		// the manager has not been started yet, so there is nothing to report
+		return
+	}
+	for _, home := range snapshot.Homes {
+		for _, node := range home.Nodes {
+			c.collectNode(ch, home.HomeId, node.NodeId)
+		}
+	}
+}
+
+func (c *Collector) collectNode(ch chan<- prometheus.Metric, homeId uint32, nodeId uint8) {
+	values := []string{fmt.Sprintf("0x%08x", homeId), fmt.Sprintf("%d", nodeId)}
+
+	if stats, err := c.api.GetNodeStatistics(homeId, nodeId); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.sent, prometheus.CounterValue, float64(stats.SentCount), values...)
+		ch <- prometheus.MustNewConstMetric(c.failed, prometheus.CounterValue, float64(stats.SentFailed), values...)
+		ch <- prometheus.MustNewConstMetric(c.retries, prometheus.CounterValue, float64(stats.RetryCount), values...)
+		ch <- prometheus.MustNewConstMetric(c.received, prometheus.CounterValue, float64(stats.ReceivedPackets), values...)
+		ch <- prometheus.MustNewConstMetric(c.rtt, prometheus.GaugeValue, float64(stats.AverageRequestRTT), values...)
+	}
+
+	if level, ok, err := c.api.GetBatteryLevel(homeId, nodeId); err == nil && ok {
+		ch <- prometheus.MustNewConstMetric(c.battery, prometheus.GaugeValue, float64(level), values...)
+	}
+}
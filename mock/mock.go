@@ -0,0 +1,942 @@
+//
+// Package mock provides an implementation of openzwave.API that does not require
+// OpenZWave or a controller, so that code written against the API can be unit tested.
+//
+// A MockAPI records each call that changes the state of a network, remembers the
+// values that are set so that they can be read back, and delivers notifications
+// injected by the test to its NotificationCallback and subscribers. For example:
+//
+//	api := mock.NewMockAPI()
+//	driver.Start(api)
+//	api.Inject(notification)
+//	if len(api.CallsTo("SetBoolValue")) != 1 {
+//		t.Fatal("expected the switch to be set")
+//	}
+//
+// A MockAPI can also stand in for a controller while a user interface is developed:
+// Simulate announces a network scripted by a SimConfig, and Echo reports each value that
+// is set as a VALUE_CHANGED notification. Importing the package also provides the simulated
+// network run by openzwave.Configurator.WithSimulator.
+//
+package mock
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ninjasphere/go-openzwave"
+	"github.com/ninjasphere/go-openzwave/NT"
+)
+
+// A call made to the mock, with its arguments in the order they were passed.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Identifies a value of a network.
+type ValueKey struct {
+	HomeId  uint32
+	ValueId uint64
+}
+
+// Identifies a node of a network.
+type NodeKey struct {
+	HomeId uint32
+	NodeId uint8
+}
+
+//
+// A MockAPI implements openzwave.API in memory.
+//
+// The exported fields may be set by the test before the mock is used. Methods that are
+// not otherwise simulated answer zero values. If Err is set, every method that can fail
+// answers Err instead.
+//
+type MockAPI struct {
+	// if not nil, receives each injected notification before the subscribers
+	Callback openzwave.NotificationCallback
+	// if not nil, answered by every method that answers an error
+	Err error
+	// the values that are read by the Get*Value methods and written by the Set*Value methods
+	Values map[ValueKey]interface{}
+	// the names and locations that are read and written by the node methods
+	NodeNames     map[NodeKey]string
+	NodeLocations map[NodeKey]string
+	// the snapshot answered by Snapshot
+	NetworkSnapshot openzwave.NetworkSnapshot
+	// the networks for which IsNetworkReady answers true
+	ReadyNetworks map[uint32]bool
+	// the device names answered by DeviceName and DeviceNames
+	Devices []string
+	// if true, each value that is set is announced with a VALUE_CHANGED notification, or VALUE_REFRESHED if
+	// it was already set. The notifications are injected in the order the values were set, from another
+	// goroutine, so that a subscriber may set values. SetBoolValueAck and SetBoolValueRetry wait for them.
+	Echo bool
+
+	mutex          sync.Mutex
+	calls          []Call
+	quit           chan int
+	logger         openzwave.Logger
+	subscriptions  map[<-chan openzwave.Notification]*subscription
+	scenes         map[uint8]string
+	nextSceneId    uint8
+	driverReady    []func(homeId uint32)
+	nodeChanged    []func(homeId uint32, nodeId uint8)
+	echoes         []openzwave.Notification // the echoes that have yet to be injected
+	echoing        bool                     // true while a goroutine is injecting the echoes
+	subscribed     chan struct{}            // if not nil, closed by the first Subscribe
+	firstSubscribe sync.Once
+	closed         chan struct{} // closed by Close, to stop the scripted changes of Simulate
+	closeOnce      sync.Once
+}
+
+type subscription struct {
+	filter        openzwave.NotificationFilter
+	notifications chan openzwave.Notification
+	done          chan struct{} // closed when the channel is about to be closed, to release a blocked delivery
+	once          sync.Once
+	sending       sync.Mutex // held while a notification is delivered, so that the channel is not closed during a send
+	closed        bool       // true once the channel has been closed, guarded by sending
+}
+
+func newSubscription(filter openzwave.NotificationFilter) *subscription {
+	return &subscription{
+		filter:        filter,
+		notifications: make(chan openzwave.Notification),
+		done:          make(chan struct{}),
+	}
+}
+
+// deliver the notification, unless the channel is closed before it is received
+func (s *subscription) deliver(notification openzwave.Notification) {
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.notifications <- notification:
+	case <-s.done:
+	}
+}
+
+// close the channel, once any delivery in progress has been released
+func (s *subscription) close() {
+	s.once.Do(func() { close(s.done) })
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.notifications)
+	}
+}
+
+// create a mock with no values, nodes or scenes
+func NewMockAPI() *MockAPI {
+	return &MockAPI{
+		Values:        make(map[ValueKey]interface{}),
+		NodeNames:     make(map[NodeKey]string),
+		NodeLocations: make(map[NodeKey]string),
+		ReadyNetworks: make(map[uint32]bool),
+		quit:          make(chan int, 1),
+		logger:        &nullLogger{},
+		subscriptions: make(map[<-chan openzwave.Notification]*subscription),
+		scenes:        make(map[uint8]string),
+		nextSceneId:   1,
+		closed:        make(chan struct{}),
+	}
+}
+
+//
+// Deliver a notification to the Callback, then to each subscriber whose filter accepts it.
+// A DRIVER_READY notification is also reported to the functions registered with OnDriverReady,
+// and a notification for a node to those registered with OnNodeChanged, without coalescing.
+//
+// Delivery to subscribers blocks until they receive the notification, or until their
+// channel is closed by Unsubscribe or Close, so a test that subscribes must receive from
+// the channel in another goroutine.
+//
+func (m *MockAPI) Inject(notification openzwave.Notification) {
+	if m.Callback != nil {
+		m.Callback(m, notification)
+	}
+	m.mutex.Lock()
+	driverReady := append([]func(uint32){}, m.driverReady...)
+	nodeChanged := append([]func(uint32, uint8){}, m.nodeChanged...)
+	m.mutex.Unlock()
+	if notification.GetNotificationType().Code == NT.DRIVER_READY {
+		for _, callback := range driverReady {
+			callback(notification.GetHomeId())
+		}
+	}
+	if notification.GetNodeId() != 0 {
+		for _, callback := range nodeChanged {
+			callback(notification.GetHomeId(), notification.GetNodeId())
+		}
+	}
+	m.mutex.Lock()
+	subscribers := make([]*subscription, 0, len(m.subscriptions))
+	for _, s := range m.subscriptions {
+		subscribers = append(subscribers, s)
+	}
+	m.mutex.Unlock()
+	for _, s := range subscribers {
+		if s.filter.Accepts(notification) {
+			s.deliver(notification)
+		}
+	}
+}
+
+// answer a copy of the calls made to the mock, in the order they were made
+func (m *MockAPI) Calls() []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// answer the calls made to the specified method, in the order they were made
+func (m *MockAPI) CallsTo(method string) []Call {
+	result := []Call{}
+	for _, call := range m.Calls() {
+		if call.Method == method {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+// set the logger answered by Logger. The default logger discards every message.
+func (m *MockAPI) SetLogger(logger openzwave.Logger) {
+	m.logger = logger
+}
+
+// record a call, answering Err
+func (m *MockAPI) record(method string, args ...interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.calls = append(m.calls, Call{method, args})
+	return m.Err
+}
+
+// inject the notification after the echoes that are already queued, without blocking the caller
+func (m *MockAPI) echo(notification openzwave.Notification) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.echoes = append(m.echoes, notification)
+	if !m.echoing {
+		m.echoing = true
+		go m.injectEchoes()
+	}
+}
+
+// inject the queued echoes in order, until none are left
+func (m *MockAPI) injectEchoes() {
+	for {
+		m.mutex.Lock()
+		if len(m.echoes) == 0 {
+			m.echoing = false
+			m.mutex.Unlock()
+			return
+		}
+		next := m.echoes[0]
+		m.echoes = m.echoes[1:]
+		m.mutex.Unlock()
+		m.Inject(next)
+	}
+}
+
+// record a call that sets a value, then store the value
+func (m *MockAPI) setValue(method string, homeId uint32, valueId uint64, value interface{}) error {
+	if err := m.record(method, homeId, valueId, value); err != nil {
+		return err
+	}
+	changed := m.storeValue(homeId, valueId, value)
+	if m.Echo {
+		if changed {
+			m.echo(valueNotification(homeId, valueId, NT.VALUE_CHANGED))
+		} else {
+			m.echo(valueNotification(homeId, valueId, NT.VALUE_REFRESHED))
+		}
+	}
+	return nil
+}
+
+// answer the stored value, with ok false if no value is stored
+func (m *MockAPI) getValue(homeId uint32, valueId uint64) (interface{}, bool, error) {
+	if m.Err != nil {
+		return nil, false, m.Err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	value, ok := m.Values[ValueKey{homeId, valueId}]
+	return value, ok, nil
+}
+
+func (m *MockAPI) QuitSignal() chan int {
+	return m.quit
+}
+
+func (m *MockAPI) Logger() openzwave.Logger {
+	return m.logger
+}
+
+func (m *MockAPI) DeviceName() string {
+	if len(m.Devices) == 0 {
+		return ""
+	}
+	return m.Devices[0]
+}
+
+func (m *MockAPI) DeviceNames() []string {
+	return m.Devices
+}
+
+// records the call and sends the exit code to the QuitSignal channel, if it is not already full
+func (m *MockAPI) Shutdown(exit int) {
+	m.record("Shutdown", exit)
+	select {
+	case m.quit <- exit:
+	default:
+	}
+}
+
+// closes every subscription channel, as the API does once the manager has stopped, releasing any delivery that is blocked on one.
+// Simulate makes no further changes once the mock has been closed.
+func (m *MockAPI) Close() error {
+	err := m.record("Close")
+	m.closeOnce.Do(func() {
+		if m.closed != nil {
+			close(m.closed)
+		}
+	})
+	m.mutex.Lock()
+	subscribers := m.subscriptions
+	m.subscriptions = make(map[<-chan openzwave.Notification]*subscription)
+	m.mutex.Unlock()
+	for _, s := range subscribers {
+		s.close()
+	}
+	return err
+}
+
+func (m *MockAPI) RestartDriver() error {
+	return m.record("RestartDriver")
+}
+
+func (m *MockAPI) OnDriverReady(callback func(homeId uint32)) {
+	m.record("OnDriverReady")
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.driverReady = append(m.driverReady, callback)
+}
+
+func (m *MockAPI) OnNodeChanged(callback func(homeId uint32, nodeId uint8)) {
+	m.record("OnNodeChanged")
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.nodeChanged = append(m.nodeChanged, callback)
+}
+
+func (m *MockAPI) IsNetworkReady(homeId uint32) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.ReadyNetworks[homeId]
+}
+
+func (m *MockAPI) CachedValue(homeId uint32, valueId uint64) (interface{}, bool) {
+	value, ok, _ := m.getValue(homeId, valueId)
+	return value, ok
+}
+
+func (m *MockAPI) Snapshot() (openzwave.NetworkSnapshot, error) {
+	return m.NetworkSnapshot, m.Err
+}
+
+func (m *MockAPI) Subscribe(filter openzwave.NotificationFilter) <-chan openzwave.Notification {
+	s := newSubscription(filter)
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.subscriptions[s.notifications] = s
+	if m.subscribed != nil {
+		m.firstSubscribe.Do(func() { close(m.subscribed) })
+	}
+	return s.notifications
+}
+
+func (m *MockAPI) NotificationsForHome(homeId uint32) <-chan openzwave.Notification {
+	return m.Subscribe(openzwave.NotificationFilter{HomeIds: []uint32{homeId}})
+}
+
+func (m *MockAPI) Unsubscribe(notifications <-chan openzwave.Notification) {
+	m.mutex.Lock()
+	s, ok := m.subscriptions[notifications]
+	delete(m.subscriptions, notifications)
+	m.mutex.Unlock()
+	if ok {
+		s.close()
+	}
+}
+
+// answers 0, since delivery to the subscribers of the mock always blocks
+func (m *MockAPI) GetDroppedNotifications() uint64 {
+	return 0
+}
+
+func (m *MockAPI) WaitForNodeReady(ctx context.Context, homeId uint32, nodeId uint8) error {
+	if err := m.record("WaitForNodeReady", homeId, nodeId); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (m *MockAPI) WaitForValueChange(ctx context.Context, homeId uint32, valueId uint64) (openzwave.Notification, error) {
+	if err := m.record("WaitForValueChange", homeId, valueId); err != nil {
+		return nil, err
+	}
+	return nil, ctx.Err()
+}
+
+func (m *MockAPI) SetBoolValue(homeId uint32, valueId uint64, value bool) error {
+	return m.setValue("SetBoolValue", homeId, valueId, value)
+}
+
+// sets the value, then waits for its echo as the API does if Echo is set
+func (m *MockAPI) SetBoolValueAck(ctx context.Context, homeId uint32, valueId uint64, value bool) error {
+	if !m.Echo {
+		return m.setValue("SetBoolValueAck", homeId, valueId, value)
+	}
+	if err := m.record("SetBoolValueAck", homeId, valueId, value); err != nil {
+		return err
+	}
+	return openzwave.SetBoolValueAckWith(ctx, m, homeId, valueId, value)
+}
+
+// sets the value, then waits for its echo as the API does if Echo is set
+func (m *MockAPI) SetBoolValueRetry(homeId uint32, valueId uint64, value bool, attempts int, backoff time.Duration) error {
+	if !m.Echo {
+		return m.setValue("SetBoolValueRetry", homeId, valueId, value)
+	}
+	if err := m.record("SetBoolValueRetry", homeId, valueId, value); err != nil {
+		return err
+	}
+	return openzwave.SetBoolValueRetryWith(m, homeId, valueId, value, attempts, backoff)
+}
+
+func (m *MockAPI) QueueSetValueOnWake(homeId uint32, valueId uint64, value interface{}) error {
+	return m.setValue("QueueSetValueOnWake", homeId, valueId, value)
+}
+
+func (m *MockAPI) PendingWrites(homeId uint32, nodeId uint8) []openzwave.PendingWrite {
+	return []openzwave.PendingWrite{}
+}
+
+func (m *MockAPI) SetIntValue(homeId uint32, valueId uint64, value int32) error {
+	return m.setValue("SetIntValue", homeId, valueId, value)
+}
+
+func (m *MockAPI) SetByteValue(homeId uint32, valueId uint64, value uint8) error {
+	return m.setValue("SetByteValue", homeId, valueId, value)
+}
+
+func (m *MockAPI) SetShortValue(homeId uint32, valueId uint64, value int16) error {
+	return m.setValue("SetShortValue", homeId, valueId, value)
+}
+
+func (m *MockAPI) SetStringValue(homeId uint32, valueId uint64, value string) error {
+	return m.setValue("SetStringValue", homeId, valueId, value)
+}
+
+func (m *MockAPI) GetBoolValue(homeId uint32, valueId uint64) (bool, bool, error) {
+	value, _, err := m.getValue(homeId, valueId)
+	result, ok := value.(bool)
+	return result, ok, err
+}
+
+func (m *MockAPI) GetIntValue(homeId uint32, valueId uint64) (int32, bool, error) {
+	value, _, err := m.getValue(homeId, valueId)
+	result, ok := value.(int32)
+	return result, ok, err
+}
+
+func (m *MockAPI) GetByteValue(homeId uint32, valueId uint64) (uint8, bool, error) {
+	value, _, err := m.getValue(homeId, valueId)
+	result, ok := value.(uint8)
+	return result, ok, err
+}
+
+func (m *MockAPI) GetStringValue(homeId uint32, valueId uint64) (string, bool, error) {
+	value, _, err := m.getValue(homeId, valueId)
+	result, ok := value.(string)
+	return result, ok, err
+}
+
+func (m *MockAPI) GetValueAsFloat(homeId uint32, valueId uint64) (float64, bool, error) {
+	value, _, err := m.getValue(homeId, valueId)
+	result, ok := value.(float64)
+	return result, ok, err
+}
+
+func (m *MockAPI) SetDecimalValue(homeId uint32, valueId uint64, value float64) error {
+	return m.setValue("SetDecimalValue", homeId, valueId, value)
+}
+
+func (m *MockAPI) GetValueAsRaw(homeId uint32, valueId uint64) ([]byte, error) {
+	value, _, err := m.getValue(homeId, valueId)
+	result, _ := value.([]byte)
+	return result, err
+}
+
+func (m *MockAPI) SetRawValue(homeId uint32, valueId uint64, value []byte) error {
+	return m.setValue("SetRawValue", homeId, valueId, value)
+}
+
+// answers the stored value formatted with fmt.Sprint, or "" if there is no stored value
+func (m *MockAPI) GetValueAsString(homeId uint32, valueId uint64) (string, error) {
+	value, ok, err := m.getValue(homeId, valueId)
+	if !ok {
+		return "", err
+	}
+	return fmt.Sprint(value), err
+}
+
+func (m *MockAPI) GetValueLabel(homeId uint32, valueId uint64) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) GetValueHelp(homeId uint32, valueId uint64) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) GetValueUnits(homeId uint32, valueId uint64) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) GetValueMin(homeId uint32, valueId uint64) (int32, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	return 0, openzwave.ErrNoValueRange
+}
+
+func (m *MockAPI) GetValueMax(homeId uint32, valueId uint64) (int32, error) {
+	if m.Err != nil {
+		return 0, m.Err
+	}
+	return 0, openzwave.ErrNoValueRange
+}
+
+func (m *MockAPI) IsValueReadOnly(homeId uint32, valueId uint64) (bool, error) {
+	return false, m.Err
+}
+
+func (m *MockAPI) IsValueWriteOnly(homeId uint32, valueId uint64) (bool, error) {
+	return false, m.Err
+}
+
+func (m *MockAPI) GetValueListItems(homeId uint32, valueId uint64) ([]string, error) {
+	return []string{}, m.Err
+}
+
+func (m *MockAPI) GetValueListSelection(homeId uint32, valueId uint64) (string, error) {
+	value, _, err := m.getValue(homeId, valueId)
+	result, _ := value.(string)
+	return result, err
+}
+
+func (m *MockAPI) SetValueListSelection(homeId uint32, valueId uint64, item string) error {
+	return m.setValue("SetValueListSelection", homeId, valueId, item)
+}
+
+func (m *MockAPI) BeginInclusion(homeId uint32, secure bool) error {
+	return m.record("BeginInclusion", homeId, secure)
+}
+
+func (m *MockAPI) BeginExclusion(homeId uint32) error {
+	return m.record("BeginExclusion", homeId)
+}
+
+func (m *MockAPI) HasNodeFailed(homeId uint32, nodeId uint8) (bool, error) {
+	return false, m.Err
+}
+
+func (m *MockAPI) RemoveFailedNode(homeId uint32, nodeId uint8) error {
+	return m.record("RemoveFailedNode", homeId, nodeId)
+}
+
+func (m *MockAPI) ReplaceFailedNode(homeId uint32, nodeId uint8) error {
+	return m.record("ReplaceFailedNode", homeId, nodeId)
+}
+
+func (m *MockAPI) SendNodeInformation(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.record("SendNodeInformation", homeId, nodeId)
+}
+
+func (m *MockAPI) AssignReturnRoute(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.record("AssignReturnRoute", homeId, nodeId)
+}
+
+func (m *MockAPI) DeleteAllReturnRoutes(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.record("DeleteAllReturnRoutes", homeId, nodeId)
+}
+
+func (m *MockAPI) HealNetworkNode(homeId uint32, nodeId uint8, doRR bool) error {
+	return m.record("HealNetworkNode", homeId, nodeId, doRR)
+}
+
+func (m *MockAPI) HealNetwork(homeId uint32, doRR bool) error {
+	return m.record("HealNetwork", homeId, doRR)
+}
+
+func (m *MockAPI) TestNetworkNode(homeId uint32, nodeId uint8, count uint32) error {
+	return m.record("TestNetworkNode", homeId, nodeId, count)
+}
+
+func (m *MockAPI) TestNetwork(homeId uint32, count uint32) error {
+	return m.record("TestNetwork", homeId, count)
+}
+
+func (m *MockAPI) CancelControllerCommand(homeId uint32) (bool, error) {
+	return true, m.record("CancelControllerCommand", homeId)
+}
+
+// answers 1, the usual node id of a controller
+func (m *MockAPI) GetControllerNodeId(homeId uint32) (uint8, error) {
+	return 1, m.Err
+}
+
+func (m *MockAPI) IsPrimaryController(homeId uint32) (bool, error) {
+	return true, m.Err
+}
+
+func (m *MockAPI) IsStaticUpdateController(homeId uint32) (bool, error) {
+	return true, m.Err
+}
+
+func (m *MockAPI) WriteConfig(homeId uint32) error {
+	return m.record("WriteConfig", homeId)
+}
+
+func (m *MockAPI) GetSendQueueCount(homeId uint32) (int32, error) {
+	return 0, m.Err
+}
+
+func (m *MockAPI) SoftReset(homeId uint32) error {
+	return m.record("SoftReset", homeId)
+}
+
+func (m *MockAPI) HardReset(homeId uint32, confirm bool) error {
+	return m.record("HardReset", homeId, confirm)
+}
+
+func (m *MockAPI) GetDriverStatistics(homeId uint32) (openzwave.DriverStats, error) {
+	return openzwave.DriverStats{}, m.Err
+}
+
+func (m *MockAPI) GetNodeStatistics(homeId uint32, nodeId uint8) (openzwave.NodeStats, error) {
+	return openzwave.NodeStats{}, m.Err
+}
+
+func (m *MockAPI) GetNodeName(homeId uint32, nodeId uint8) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.NodeNames[NodeKey{homeId, nodeId}], m.Err
+}
+
+func (m *MockAPI) SetNodeName(homeId uint32, nodeId uint8, name string) error {
+	if err := m.record("SetNodeName", homeId, nodeId, name); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.NodeNames[NodeKey{homeId, nodeId}] = name
+	return nil
+}
+
+func (m *MockAPI) GetNodeLocation(homeId uint32, nodeId uint8) (string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.NodeLocations[NodeKey{homeId, nodeId}], m.Err
+}
+
+func (m *MockAPI) SetNodeLocation(homeId uint32, nodeId uint8, location string) error {
+	if err := m.record("SetNodeLocation", homeId, nodeId, location); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.NodeLocations[NodeKey{homeId, nodeId}] = location
+	return nil
+}
+
+func (m *MockAPI) GetNodeManufacturerName(homeId uint32, nodeId uint8) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) GetNodeProductName(homeId uint32, nodeId uint8) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) GetNodeProductType(homeId uint32, nodeId uint8) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) GetNodeProductId(homeId uint32, nodeId uint8) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) RefreshNodeInfo(homeId uint32, nodeId uint8) error {
+	return m.record("RefreshNodeInfo", homeId, nodeId)
+}
+
+// answers "Complete", the final query stage
+func (m *MockAPI) GetNodeQueryStage(homeId uint32, nodeId uint8) (string, error) {
+	return "Complete", m.Err
+}
+
+func (m *MockAPI) IsNodeInfoReceived(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.Err
+}
+
+func (m *MockAPI) RequestNodeState(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.record("RequestNodeState", homeId, nodeId)
+}
+
+func (m *MockAPI) RequestNodeDynamic(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.record("RequestNodeDynamic", homeId, nodeId)
+}
+
+func (m *MockAPI) GetBatteryLevel(homeId uint32, nodeId uint8) (uint8, bool, error) {
+	return 0, false, m.Err
+}
+
+func (m *MockAPI) SetWakeupInterval(homeId uint32, nodeId uint8, seconds uint32) error {
+	return m.record("SetWakeupInterval", homeId, nodeId, seconds)
+}
+
+func (m *MockAPI) SetBasic(homeId uint32, nodeId uint8, level uint8) error {
+	return m.record("SetBasic", homeId, nodeId, level)
+}
+
+func (m *MockAPI) GetBasic(homeId uint32, nodeId uint8) (uint8, bool, error) {
+	return 0, false, m.Err
+}
+
+func (m *MockAPI) GetNodeNeighbors(homeId uint32, nodeId uint8) ([]uint8, error) {
+	return []uint8{}, m.Err
+}
+
+func (m *MockAPI) IsNodeListeningDevice(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.Err
+}
+
+func (m *MockAPI) IsNodeFrequentListeningDevice(homeId uint32, nodeId uint8) (bool, error) {
+	return false, m.Err
+}
+
+func (m *MockAPI) IsNodeBeamingDevice(homeId uint32, nodeId uint8) (bool, error) {
+	return false, m.Err
+}
+
+func (m *MockAPI) IsNodeRoutingDevice(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.Err
+}
+
+func (m *MockAPI) IsNodeSecurityDevice(homeId uint32, nodeId uint8) (bool, error) {
+	return false, m.Err
+}
+
+func (m *MockAPI) GetNodeBasicType(homeId uint32, nodeId uint8) (uint8, error) {
+	return 0, m.Err
+}
+
+func (m *MockAPI) GetNodeGenericType(homeId uint32, nodeId uint8) (uint8, error) {
+	return 0, m.Err
+}
+
+func (m *MockAPI) GetNodeSpecificType(homeId uint32, nodeId uint8) (uint8, error) {
+	return 0, m.Err
+}
+
+func (m *MockAPI) GetNodeType(homeId uint32, nodeId uint8) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) IsNodeAwake(homeId uint32, nodeId uint8) (bool, error) {
+	return true, m.Err
+}
+
+func (m *MockAPI) GetNodeValueIDs(homeId uint32, nodeId uint8) ([]uint64, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	result := []uint64{}
+	for key := range m.Values {
+		if key.HomeId == homeId && uint8(key.ValueId>>24) == nodeId {
+			result = append(result, key.ValueId)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+func (m *MockAPI) GetValuesByInstance(homeId uint32, nodeId uint8, commandClassId uint8, instance uint8) ([]uint64, error) {
+	values, err := m.GetNodeValueIDs(homeId, nodeId)
+	if err != nil {
+		return nil, err
+	}
+	result := []uint64{}
+	for _, valueId := range values {
+		if uint8(valueId>>14) == commandClassId && uint8(valueId>>56) == instance {
+			result = append(result, valueId)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockAPI) GetNumGroups(homeId uint32, nodeId uint8) (uint8, error) {
+	return 0, m.Err
+}
+
+func (m *MockAPI) GetGroupLabel(homeId uint32, nodeId uint8, group uint8) (string, error) {
+	return "", m.Err
+}
+
+func (m *MockAPI) GetAssociations(homeId uint32, nodeId uint8, group uint8) ([]uint8, error) {
+	return []uint8{}, m.Err
+}
+
+func (m *MockAPI) AddAssociation(homeId uint32, nodeId uint8, group uint8, target uint8) error {
+	return m.record("AddAssociation", homeId, nodeId, group, target)
+}
+
+func (m *MockAPI) RemoveAssociation(homeId uint32, nodeId uint8, group uint8, target uint8) error {
+	return m.record("RemoveAssociation", homeId, nodeId, group, target)
+}
+
+func (m *MockAPI) SetConfigParam(homeId uint32, nodeId uint8, param uint8, value int32, size uint8) (bool, error) {
+	return true, m.record("SetConfigParam", homeId, nodeId, param, value, size)
+}
+
+func (m *MockAPI) RequestConfigParam(homeId uint32, nodeId uint8, param uint8) error {
+	return m.record("RequestConfigParam", homeId, nodeId, param)
+}
+
+func (m *MockAPI) RequestAllConfigParams(homeId uint32, nodeId uint8) error {
+	return m.record("RequestAllConfigParams", homeId, nodeId)
+}
+
+// answers a new scene id, starting from 1
+func (m *MockAPI) CreateScene() (uint8, error) {
+	if err := m.record("CreateScene"); err != nil {
+		return 0, err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	sceneId := m.nextSceneId
+	m.nextSceneId++
+	m.scenes[sceneId] = ""
+	return sceneId, nil
+}
+
+func (m *MockAPI) RemoveScene(sceneId uint8) error {
+	if err := m.record("RemoveScene", sceneId); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.scenes[sceneId]; !ok {
+		return fmt.Errorf("unknown scene %d", sceneId)
+	}
+	delete(m.scenes, sceneId)
+	return nil
+}
+
+func (m *MockAPI) GetScenes() ([]uint8, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	result := []uint8{}
+	for sceneId := range m.scenes {
+		result = append(result, sceneId)
+	}
+	return result, m.Err
+}
+
+func (m *MockAPI) SetSceneLabel(sceneId uint8, label string) error {
+	if err := m.record("SetSceneLabel", sceneId, label); err != nil {
+		return err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.scenes[sceneId]; !ok {
+		return fmt.Errorf("unknown scene %d", sceneId)
+	}
+	m.scenes[sceneId] = label
+	return nil
+}
+
+func (m *MockAPI) AddSceneBoolValue(sceneId uint8, homeId uint32, valueId uint64, value bool) error {
+	return m.record("AddSceneBoolValue", sceneId, homeId, valueId, value)
+}
+
+func (m *MockAPI) AddSceneIntValue(sceneId uint8, homeId uint32, valueId uint64, value int32) error {
+	return m.record("AddSceneIntValue", sceneId, homeId, valueId, value)
+}
+
+func (m *MockAPI) AddSceneByteValue(sceneId uint8, homeId uint32, valueId uint64, value uint8) error {
+	return m.record("AddSceneByteValue", sceneId, homeId, valueId, value)
+}
+
+func (m *MockAPI) AddSceneStringValue(sceneId uint8, homeId uint32, valueId uint64, value string) error {
+	return m.record("AddSceneStringValue", sceneId, homeId, valueId, value)
+}
+
+func (m *MockAPI) RemoveSceneValue(sceneId uint8, homeId uint32, valueId uint64) error {
+	return m.record("RemoveSceneValue", sceneId, homeId, valueId)
+}
+
+func (m *MockAPI) ActivateScene(sceneId uint8) error {
+	return m.record("ActivateScene", sceneId)
+}
+
+func (m *MockAPI) EnablePoll(homeId uint32, valueId uint64, intensity uint8) (bool, error) {
+	return true, m.record("EnablePoll", homeId, valueId, intensity)
+}
+
+func (m *MockAPI) DisablePoll(homeId uint32, valueId uint64) (bool, error) {
+	return true, m.record("DisablePoll", homeId, valueId)
+}
+
+func (m *MockAPI) SetPollInterval(milliseconds int32, intervalBetweenPolls bool) error {
+	return m.record("SetPollInterval", milliseconds, intervalBetweenPolls)
+}
+
+// answers true if EnablePoll was called for the value more recently than DisablePoll
+func (m *MockAPI) IsPolled(homeId uint32, valueId uint64) (bool, error) {
+	polled := false
+	for _, call := range m.Calls() {
+		if (call.Method == "EnablePoll" || call.Method == "DisablePoll") &&
+			call.Args[0] == homeId && call.Args[1] == valueId {
+			polled = call.Method == "EnablePoll"
+		}
+	}
+	return polled, m.Err
+}
+
+// a logger that discards every message
+type nullLogger struct {
+}
+
+func (*nullLogger) Infof(message string, args ...interface{})    {}
+func (*nullLogger) Warningf(message string, args ...interface{}) {}
+func (*nullLogger) Errorf(message string, args ...interface{})   {}
+func (*nullLogger) Debugf(message string, args ...interface{})   {}
+func (*nullLogger) Tracef(message string, args ...interface{})   {}
+
+// the mock must implement the whole API
+var _ openzwave.API = (*MockAPI)(nil)
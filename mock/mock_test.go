@@ -0,0 +1,136 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ninjasphere/go-openzwave"
+	"github.com/ninjasphere/go-openzwave/CC"
+	"github.com/ninjasphere/go-openzwave/NT"
+	"github.com/ninjasphere/go-openzwave/VG"
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+func TestUnsubscribeDuringInject(t *testing.T) {
+	m := NewMockAPI()
+	notifications := m.Subscribe(openzwave.NotificationFilter{})
+
+	injected := make(chan struct{})
+	go func() {
+		m.Inject(openzwave.NewNotification(1, 2, NT.ToEnum(NT.VALUE_CHANGED), nil, 0, VT.ToEnum(VT.BOOL)))
+		close(injected)
+	}()
+	time.Sleep(10 * time.Millisecond) // let the delivery block, since nothing receives it
+
+	m.Unsubscribe(notifications)
+	select {
+	case <-injected:
+	case <-time.After(time.Second):
+		t.Fatal("the delivery was not released by Unsubscribe")
+	}
+	if _, ok := <-notifications; ok {
+		t.Fatal("expected the unsubscribed channel to be closed")
+	}
+}
+
+func TestWithSimulator(t *testing.T) {
+	const homeId = 0xdeadbeef
+	lamp := openzwave.ValueID{CommandClassId: CC.SWITCH_BINARY, Instance: 1, NodeId: 2, Genre: VG.USER, Type: VT.BOOL}.Encode()
+	config := openzwave.SimConfig{
+		HomeId: homeId,
+		Nodes:  []openzwave.SimNode{{NodeId: 2, Name: "Lamp", Values: []openzwave.SimValue{{ValueId: lamp, Value: false}}}},
+	}
+
+	changed := make(chan bool, 1)
+	loop := func(api openzwave.API) int {
+		notifications := api.Subscribe(openzwave.NotificationFilter{NotificationTypes: []int{NT.VALUE_ADDED, NT.VALUE_CHANGED}})
+		defer api.Unsubscribe(notifications)
+		for {
+			select {
+			case n := <-notifications:
+				if n.GetNotificationType().Code == NT.VALUE_ADDED {
+					if err := api.SetBoolValue(n.GetHomeId(), n.GetValueId(), true); err != nil {
+						t.Errorf("failed to set the simulated value: %v", err)
+					}
+					continue
+				}
+				value, _, err := api.GetBoolValue(n.GetHomeId(), n.GetValueId())
+				if err != nil {
+					t.Errorf("failed to get the simulated value: %v", err)
+				}
+				changed <- value
+			case rc := <-api.QuitSignal():
+				return rc
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	dir := t.TempDir()
+	result := make(chan int, 1)
+	go func() {
+		result <- openzwave.BuildAPI(dir, dir, "").WithSimulator(config).SetEventLoop(loop).RunContext(ctx)
+	}()
+
+	select {
+	case value := <-changed:
+		if !value {
+			t.Fatal("expected the echo of the value that was set")
+		}
+	case <-ctx.Done():
+		t.Fatal("the value that was set was not echoed")
+	}
+	cancel()
+	if rc := <-result; rc != 0 {
+		t.Fatalf("expected a cancelled simulation to exit with 0, not %d", rc)
+	}
+}
+
+func TestSetBoolValueRetryAcceptsARefresh(t *testing.T) {
+	const homeId = 0xdeadbeef
+	lamp := openzwave.ValueID{CommandClassId: CC.SWITCH_BINARY, Instance: 1, NodeId: 2, Genre: VG.USER, Type: VT.BOOL}.Encode()
+	m := NewMockAPI()
+	m.Echo = true
+
+	// the first write changes the value, and the second is answered with VALUE_REFRESHED
+	for _, value := range []bool{true, true} {
+		if err := m.SetBoolValueRetry(homeId, lamp, value, 2, time.Second); err != nil {
+			t.Fatalf("failed to set the value to %v: %v", value, err)
+		}
+	}
+	if calls := m.CallsTo("SetBoolValue"); len(calls) != 2 {
+		t.Fatalf("expected each write to succeed at the first attempt, got %d attempts", len(calls))
+	}
+}
+
+func TestCloseStopsTheSimulator(t *testing.T) {
+	config := openzwave.SimConfig{HomeId: 0xdeadbeef}
+
+	running := make(chan struct{})
+	loop := func(api openzwave.API) int {
+		close(running)
+		return <-api.QuitSignal()
+	}
+
+	dir := t.TempDir()
+	configurator := openzwave.BuildAPI(dir, dir, "").WithSimulator(config).SetEventLoop(loop)
+	result := make(chan int, 1)
+	go func() {
+		result <- configurator.RunContext(context.Background())
+	}()
+
+	<-running
+	if err := configurator.(openzwave.API).Close(); err != nil {
+		t.Fatalf("failed to close the simulated network: %v", err)
+	}
+	select {
+	case rc := <-result:
+		if rc != 0 {
+			t.Fatalf("expected a closed simulation to exit with 0, not %d", rc)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the simulated run did not end when it was closed")
+	}
+}
@@ -0,0 +1,139 @@
+package mock
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/ninjasphere/go-openzwave"
+	"github.com/ninjasphere/go-openzwave/NT"
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+// A scripted network, announced by Simulate. See openzwave.SimConfig.
+type SimConfig = openzwave.SimConfig
+
+// A node of a simulated network, and the initial state of its values.
+type SimNode = openzwave.SimNode
+
+// A value of a simulated node.
+type SimValue = openzwave.SimValue
+
+// A scripted change of a value.
+type SimChange = openzwave.SimChange
+
+// load a SimConfig from a JSON file
+func LoadSimConfig(path string) (SimConfig, error) {
+	return openzwave.LoadSimConfig(path)
+}
+
+// provide the simulated network of openzwave.Configurator.WithSimulator
+func init() {
+	openzwave.RegisterSimulator(newSimulatedAPI)
+}
+
+// the time a simulated network waits for the event loop to subscribe before it is announced anyway
+const simulatorStartTimeout = time.Second
+
+//
+// Create a mock that announces the network described by the config, and echoes the values
+// that are set. The network is announced once the event loop first subscribes, so that the
+// subscriber receives the whole announcement, or after a second if it does not subscribe.
+//
+func newSimulatedAPI(config SimConfig, logger openzwave.Logger, callback openzwave.NotificationCallback) openzwave.API {
+	m := NewMockAPI()
+	m.Echo = true
+	m.Callback = callback
+	if logger != nil {
+		m.logger = logger
+	}
+	m.subscribed = make(chan struct{})
+	go func() {
+		select {
+		case <-m.subscribed:
+		case <-time.After(simulatorStartTimeout):
+		case <-m.closed:
+			return
+		}
+		m.Simulate(config)
+	}()
+	return m
+}
+
+//
+// Announce the network described by the config, then make its scripted changes.
+//
+// The notifications are those OpenZWave sends for a newly found network: DRIVER_READY, then
+// NODE_ADDED, VALUE_ADDED and NODE_QUERIES_COMPLETE for each node, then ALL_NODES_QUERIED.
+// Each value is stored before it is announced, so that the getters answer it. Each scripted
+// change is stored, then announced with VALUE_CHANGED, until the mock is closed.
+//
+// Like Inject, this blocks until each subscriber has received each notification, so it is
+// normally run in its own goroutine. Set Echo to have the Set*Value methods answer with
+// VALUE_CHANGED or VALUE_REFRESHED notifications too.
+//
+func (m *MockAPI) Simulate(config SimConfig) {
+	homeId := config.HomeId
+	m.Inject(openzwave.NewNotification(homeId, 0, NT.ToEnum(NT.DRIVER_READY), nil, 0, nil))
+
+	for _, node := range config.Nodes {
+		m.mutex.Lock()
+		m.NodeNames[NodeKey{homeId, node.NodeId}] = node.Name
+		m.NodeLocations[NodeKey{homeId, node.NodeId}] = node.Location
+		m.mutex.Unlock()
+
+		m.Inject(openzwave.NewNotification(homeId, node.NodeId, NT.ToEnum(NT.NODE_ADDED), nil, 0, nil))
+		for _, v := range node.Values {
+			m.storeValue(homeId, v.ValueId, simValue(v.ValueId, v.Value))
+			m.Inject(valueNotification(homeId, v.ValueId, NT.VALUE_ADDED))
+		}
+		m.Inject(openzwave.NewNotification(homeId, node.NodeId, NT.ToEnum(NT.NODE_QUERIES_COMPLETE), nil, 0, nil))
+	}
+
+	m.mutex.Lock()
+	m.ReadyNetworks[homeId] = true
+	m.mutex.Unlock()
+	m.Inject(openzwave.NewNotification(homeId, 0, NT.ToEnum(NT.ALL_NODES_QUERIED), nil, 0, nil))
+
+	for _, change := range config.Changes {
+		select {
+		case <-time.After(time.Duration(change.DelayMs) * time.Millisecond):
+		case <-m.closed:
+			return
+		}
+		m.storeValue(homeId, change.ValueId, simValue(change.ValueId, change.Value))
+		m.Inject(valueNotification(homeId, change.ValueId, NT.VALUE_CHANGED))
+	}
+}
+
+// store a value without recording a call, answering false if the same value was already stored
+func (m *MockAPI) storeValue(homeId uint32, valueId uint64, value interface{}) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	key := ValueKey{homeId, valueId}
+	previous, ok := m.Values[key]
+	m.Values[key] = value
+	return !ok || !reflect.DeepEqual(previous, value)
+}
+
+// create a notification of the specified type for a value
+func valueNotification(homeId uint32, valueId uint64, notificationType int) openzwave.Notification {
+	return openzwave.NewNotification(homeId, uint8(valueId>>24), NT.ToEnum(notificationType), nil, valueId, nil)
+}
+
+// convert a value decoded from JSON to the Go type used for values of its type
+func simValue(valueId uint64, value interface{}) interface{} {
+	number, isNumber := value.(float64)
+	if !isNumber {
+		return value
+	}
+	switch int(valueId & 0x0f) {
+	case VT.BYTE:
+		return uint8(number)
+	case VT.SHORT:
+		return int16(number)
+	case VT.INT:
+		return int32(number)
+	default:
+		return number
+	}
+}
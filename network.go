@@ -14,13 +14,19 @@ type Network interface {
 	GetHomeId() uint32
 }
 
+//
+// The network model. Only the notification thread changes it, so that thread may read it
+// freely, but it takes the model lock of the API to make a change, and other goroutines
+// take the read lock to read it.
+//
 type network struct {
 	homeId uint32
 	nodes  map[uint8]*node
+	ready  bool // true once the initial queries of the awake nodes are complete
 }
 
 func newNetwork(homeId uint32) *network {
-	return &network{homeId, make(map[uint8]*node)}
+	return &network{homeId, make(map[uint8]*node), false}
 }
 
 func (nw *network) GetHomeId() uint32 {
@@ -35,17 +41,24 @@ func (nw *network) notify(api *api, nt *notification) {
 	case NT.DRIVER_READY,
 		NT.DRIVER_RESET:
 		// reset network object to reset state
+		api.model.Lock()
 		nw.reset()
+		api.model.Unlock()
 		break
 
 	// group associations
-	case NT.GROUP,
-		NT.AWAKE_NODES_QUERIED,
+	case NT.GROUP:
+		unhandled(api, nt)
+		break
+
+	// move network into running state
+	case NT.AWAKE_NODES_QUERIED,
 		NT.ALL_NODES_QUERIED_SOME_DEAD,
 		NT.ALL_NODES_QUERIED:
-		unhandled(api, nt)
+		api.model.Lock()
+		nw.ready = true
+		api.model.Unlock()
 		break
-		// move network into running state
 
 	// notifications
 	case NT.NOTIFICATION:
@@ -53,7 +66,7 @@ func (nw *network) notify(api *api, nt *notification) {
 	default:
 		node := nt.GetNode()
 		if node.GetId() <= MAX_NODES {
-			nw.handleNodeEvent(api, nt, nw.takeNode(nt))
+			nw.handleNodeEvent(api, nt, nw.takeNode(api, nt))
 		} else {
 			unhandled(api, nt)
 		}
@@ -70,7 +83,9 @@ func (nw *network) handleNodeEvent(api *api, nt *notification, nodeV *node) {
 	switch notificationType {
 	case NT.NODE_REMOVED:
 		if ok {
+			api.model.Lock()
 			delete(nw.nodes, id)
+			api.model.Unlock()
 			n.notify(api, nt)
 		}
 		break
@@ -86,7 +101,9 @@ func (nw *network) handleNodeEvent(api *api, nt *notification, nodeV *node) {
 	case NT.NODE_NEW,
 		NT.NODE_ADDED:
 		if !ok {
+			api.model.Lock()
 			nw.nodes[id] = nodeV
+			api.model.Unlock()
 		}
 		fallthrough
 
@@ -110,9 +127,14 @@ func (nw *network) handleNodeEvent(api *api, nt *notification, nodeV *node) {
 
 func (nw *network) reset() {
 	nw.nodes = make(map[uint8]*node)
+	nw.ready = false
 }
 
-func (nw *network) takeNode(nt *notification) *node {
+// take the node structure from the notification, under the model lock since other goroutines may be reading the node
+func (nw *network) takeNode(api *api, nt *notification) *node {
+	api.model.Lock()
+	defer api.model.Unlock()
+
 	id := uint8(nt.node.cRef.nodeId.nodeId)
 	n, ok := nw.nodes[id]
 	if !ok {
@@ -39,3 +39,135 @@ Node * exportNode(API * api, uint32_t homeId, uint8_t nodeId)
   result->productId = strdup(cppRef->GetNodeProductId(homeId, nodeId).c_str());
   return result;
 }
+
+char * getNodeName(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeName(homeId, nodeId).c_str());
+}
+
+void setNodeName(uint32_t homeId, uint8_t nodeId, char * name)
+{
+  OpenZWave::Manager::Get()->SetNodeName(homeId, nodeId, name);
+}
+
+char * getNodeLocation(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeLocation(homeId, nodeId).c_str());
+}
+
+void setNodeLocation(uint32_t homeId, uint8_t nodeId, char * location)
+{
+  OpenZWave::Manager::Get()->SetNodeLocation(homeId, nodeId, location);
+}
+
+char * getNodeManufacturerName(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeManufacturerName(homeId, nodeId).c_str());
+}
+
+char * getNodeProductName(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeProductName(homeId, nodeId).c_str());
+}
+
+char * getNodeProductType(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeProductType(homeId, nodeId).c_str());
+}
+
+char * getNodeProductId(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeProductId(homeId, nodeId).c_str());
+}
+
+bool isNodeFailed(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeFailed(homeId, nodeId);
+}
+
+bool refreshNodeInfo(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->RefreshNodeInfo(homeId, nodeId);
+}
+
+char * getNodeQueryStage(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeQueryStage(homeId, nodeId).c_str());
+}
+
+bool isNodeInfoReceived(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeInfoReceived(homeId, nodeId);
+}
+
+bool requestNodeState(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->RequestNodeState(homeId, nodeId);
+}
+
+bool requestNodeDynamic(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->RequestNodeDynamic(homeId, nodeId);
+}
+
+// exports the neighbors of a node into an array that must be released with freeNodeNeighbors
+uint32_t getNodeNeighbors(uint32_t homeId, uint8_t nodeId, uint8_t ** neighbors)
+{
+  *neighbors = NULL;
+  return OpenZWave::Manager::Get()->GetNodeNeighbors(homeId, nodeId, neighbors);
+}
+
+void freeNodeNeighbors(uint8_t * neighbors)
+{
+  delete [] neighbors;
+}
+
+bool isNodeListeningDevice(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeListeningDevice(homeId, nodeId);
+}
+
+bool isNodeFrequentListeningDevice(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeFrequentListeningDevice(homeId, nodeId);
+}
+
+bool isNodeBeamingDevice(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeBeamingDevice(homeId, nodeId);
+}
+
+bool isNodeRoutingDevice(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeRoutingDevice(homeId, nodeId);
+}
+
+bool isNodeSecurityDevice(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeSecurityDevice(homeId, nodeId);
+}
+
+uint8_t getNodeBasic(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->GetNodeBasic(homeId, nodeId);
+}
+
+uint8_t getNodeGeneric(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->GetNodeGeneric(homeId, nodeId);
+}
+
+uint8_t getNodeSpecific(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->GetNodeSpecific(homeId, nodeId);
+}
+
+char * getNodeType(uint32_t homeId, uint8_t nodeId)
+{
+  return strdup(OpenZWave::Manager::Get()->GetNodeType(homeId, nodeId).c_str());
+}
+
+bool isNodeAwake(uint32_t homeId, uint8_t nodeId)
+{
+  return OpenZWave::Manager::Get()->IsNodeAwake(homeId, nodeId);
+}
@@ -8,7 +8,12 @@ import "C"
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
 
+	"github.com/ninjasphere/go-openzwave/CC"
 	"github.com/ninjasphere/go-openzwave/NT"
 )
 
@@ -120,7 +125,7 @@ func (n *node) notify(api *api, nt *notification) {
 		break
 
 	case NT.VALUE_REMOVED:
-		n.removeValue(nt)
+		n.removeValue(api, nt)
 		break
 
 	case NT.ESSENTIAL_NODE_QUERIES_COMPLETE,
@@ -155,7 +160,7 @@ func (n *node) notify(api *api, nt *notification) {
 	case NT.VALUE_ADDED,
 		NT.VALUE_CHANGED,
 		NT.VALUE_REFRESHED:
-		v := n.takeValue(nt)
+		v := n.takeValue(api, nt)
 		if n.device != nil {
 			n.device.ValueChanged(v)
 		}
@@ -168,8 +173,11 @@ func (n *node) notify(api *api, nt *notification) {
 	}
 }
 
-// take the value structure from the notification
-func (n *node) takeValue(nt *notification) *value {
+// take the value structure from the notification, under the model lock
+func (n *node) takeValue(api *api, nt *notification) *value {
+	api.model.Lock()
+	defer api.model.Unlock()
+
 	commandClassId := (uint8)(nt.value.cRef.valueId.commandClassId)
 	instanceId := (uint8)(nt.value.cRef.valueId.instance)
 	index := (uint8)(nt.value.cRef.valueId.index)
@@ -183,6 +191,7 @@ func (n *node) takeValue(nt *notification) *value {
 	} else {
 		nt.swapValueImpl(v)
 	}
+	v.id = uint64(v.cRef.valueId.id)
 	return v
 }
 
@@ -221,7 +230,10 @@ func (n *node) GetValueWithId(valueId ValueID) Value {
 	return n.GetValue(valueId.CommandClassId, valueId.Instance, valueId.Index)
 }
 
-func (n *node) removeValue(nt *notification) {
+func (n *node) removeValue(api *api, nt *notification) {
+	api.model.Lock()
+	defer api.model.Unlock()
+
 	commandClassId := (uint8)(nt.value.cRef.valueId.commandClassId)
 	instanceId := (uint8)(nt.value.cRef.valueId.instance)
 	index := (uint8)(nt.value.cRef.valueId.index)
@@ -273,6 +285,408 @@ func (n *node) GetNodeName() string {
 	return C.GoString(n.cRef.nodeName)
 }
 
+// release the C structure. Subsequent calls have no effect.
 func (n *node) free() {
-	C.freeNode(n.cRef)
+	if n.cRef != nil {
+		C.freeNode(n.cRef)
+		n.cRef = nil
+	}
+}
+
+// get the name of a node
+func (a *api) GetNodeName(homeId uint32, nodeId uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getNodeName(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// set the name of a node
+func (a *api) SetNodeName(homeId uint32, nodeId uint8, name string) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.setNodeName(C.uint32_t(homeId), C.uint8_t(nodeId), cName)
+	return nil
+}
+
+// get the location of a node. Any invalid UTF-8 sequences reported by the device are replaced.
+func (a *api) GetNodeLocation(homeId uint32, nodeId uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	location := takeString(C.getNodeLocation(C.uint32_t(homeId), C.uint8_t(nodeId)))
+	return strings.ToValidUTF8(location, "\uFFFD"), nil
+}
+
+// set the location of a node
+func (a *api) SetNodeLocation(homeId uint32, nodeId uint8, location string) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if !utf8.ValidString(location) {
+		return fmt.Errorf("location %q is not valid UTF-8", location)
+	}
+	cLocation := C.CString(location)
+	defer C.free(unsafe.Pointer(cLocation))
+	C.setNodeLocation(C.uint32_t(homeId), C.uint8_t(nodeId), cLocation)
+	return nil
+}
+
+//
+// The manufacturer and product getters are only meaningful once the ManufacturerSpecific
+// query of the node has completed. Until the NODE_QUERIES_COMPLETE notification has been
+// received for the node, they may return empty strings.
+//
+
+// get the manufacturer name of a node
+func (a *api) GetNodeManufacturerName(homeId uint32, nodeId uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getNodeManufacturerName(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// get the product name of a node
+func (a *api) GetNodeProductName(homeId uint32, nodeId uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getNodeProductName(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// get the product type of a node
+func (a *api) GetNodeProductType(homeId uint32, nodeId uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getNodeProductType(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// get the product id of a node
+func (a *api) GetNodeProductId(homeId uint32, nodeId uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getNodeProductId(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+//
+// Force the node to be re-interviewed.
+//
+// The query stages of the node are restarted from the beginning, so the usual notifications
+// are generated as the node is queried, ending with NODE_QUERIES_COMPLETE.
+//
+func (a *api) RefreshNodeInfo(homeId uint32, nodeId uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if !(bool)(C.refreshNodeInfo(C.uint32_t(homeId), C.uint8_t(nodeId))) {
+		return fmt.Errorf("failed to refresh node %d in network 0x%08x", nodeId, homeId)
+	}
+	return nil
+}
+
+// get the name of the query stage of a node, for example "ProtocolInfo", "NodeInfo" or "Complete"
+func (a *api) GetNodeQueryStage(homeId uint32, nodeId uint8) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getNodeQueryStage(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// answer true if the node information frame of the node has been received
+func (a *api) IsNodeInfoReceived(homeId uint32, nodeId uint8) (bool, error) {
+	if err := checkManager(); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeInfoReceived(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// request the values of all the command classes of a node. Answers true if the requests were queued.
+func (a *api) RequestNodeState(homeId uint32, nodeId uint8) (bool, error) {
+	if err := checkManager(); err != nil {
+		return false, err
+	}
+	return (bool)(C.requestNodeState(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// request the dynamic values of a node, such as sensor readings. Answers true if the requests were queued.
+func (a *api) RequestNodeDynamic(homeId uint32, nodeId uint8) (bool, error) {
+	if err := checkManager(); err != nil {
+		return false, err
+	}
+	return (bool)(C.requestNodeDynamic(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+//
+// Get the battery level of a node, as a percentage.
+//
+// ok is false if the node does not have a battery level value, either because it is
+// mains powered or because the Battery command class has not been queried yet.
+//
+func (a *api) GetBatteryLevel(homeId uint32, nodeId uint8) (uint8, bool, error) {
+	if err := checkManager(); err != nil {
+		return 0, false, err
+	}
+	valueId, ok, err := a.findValueId(homeId, nodeId, CC.BATTERY, 1, 0)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	return a.GetByteValue(homeId, valueId)
+}
+
+// set the interval, in seconds, at which a sleeping node wakes up
+func (a *api) SetWakeupInterval(homeId uint32, nodeId uint8, seconds uint32) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	valueId, ok, err := a.findValueId(homeId, nodeId, CC.WAKE_UP, 1, 0)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("node %d in network 0x%08x does not have a wake-up interval", nodeId, homeId)
+	}
+	if err := a.SetIntValue(homeId, valueId, int32(seconds)); err != nil {
+		return fmt.Errorf("failed to set the wake-up interval of node %d in network 0x%08x: %w", nodeId, homeId, err)
+	}
+	return nil
+}
+
+//
+// Find the id of a value of a node in the network model, so that it can be read or written
+// with the id based methods once the model lock has been released. ok is false if the node
+// does not have the value.
+//
+func (a *api) findValueId(homeId uint32, nodeId uint8, commandClassId uint8, instance uint8, index uint8) (uint64, bool, error) {
+	a.model.RLock()
+	defer a.model.RUnlock()
+	n, ok := a.lookupNode(homeId, nodeId)
+	if !ok {
+		return 0, false, unknownNode(homeId, nodeId)
+	}
+	v, ok := n.GetValue(commandClassId, instance, index).(*value)
+	if !ok {
+		return 0, false, nil
+	}
+	return v.id, true, nil
+}
+
+//
+// Set the level of a node with the Basic command class.
+//
+// Every node supports the Basic command class, so this is a way to switch or dim a node
+// without knowing which other command classes it supports. The meaning of the level is
+// device specific, but 0 is usually off and 0xff is usually on.
+//
+func (a *api) SetBasic(homeId uint32, nodeId uint8, level uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	valueId, ok, err := a.findValueId(homeId, nodeId, CC.BASIC, 1, 0)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("node %d in network 0x%08x does not have a basic value", nodeId, homeId)
+	}
+	if err := a.SetByteValue(homeId, valueId, level); err != nil {
+		return fmt.Errorf("failed to set the basic value of node %d in network 0x%08x: %w", nodeId, homeId, err)
+	}
+	return nil
+}
+
+// get the level of a node from the Basic command class. ok is false if the node does not have a basic value.
+func (a *api) GetBasic(homeId uint32, nodeId uint8) (uint8, bool, error) {
+	if err := checkManager(); err != nil {
+		return 0, false, err
+	}
+	valueId, ok, err := a.findValueId(homeId, nodeId, CC.BASIC, 1, 0)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	return a.GetByteValue(homeId, valueId)
+}
+
+// get the ids of the neighbors of a node. The result is empty until the neighbors of the node are known.
+func (a *api) GetNodeNeighbors(homeId uint32, nodeId uint8) ([]uint8, error) {
+	if err := checkManager(); err != nil {
+		return nil, err
+	}
+	var neighbors *C.uint8_t
+	count := C.getNodeNeighbors(C.uint32_t(homeId), C.uint8_t(nodeId), &neighbors)
+	defer C.freeNodeNeighbors(neighbors)
+
+	result := make([]uint8, int(count))
+	for i, neighbor := range unsafe.Slice(neighbors, int(count)) {
+		result[i] = (uint8)(neighbor)
+	}
+	return result, nil
+}
+
+// answer an error if the manager is not started or the node is not known to the network model
+func (a *api) checkNodeKnown(homeId uint32, nodeId uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if !a.isNodeKnown(homeId, nodeId) {
+		return unknownNode(homeId, nodeId)
+	}
+	return nil
+}
+
+// answer true if the node is always listening, that is, it is mains powered
+func (a *api) IsNodeListeningDevice(homeId uint32, nodeId uint8) (bool, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeListeningDevice(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// answer true if the node is a frequently listening (FLiRS) device, which wakes up when beamed
+func (a *api) IsNodeFrequentListeningDevice(homeId uint32, nodeId uint8) (bool, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeFrequentListeningDevice(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// answer true if the node supports beaming, so that it can wake FLiRS devices
+func (a *api) IsNodeBeamingDevice(homeId uint32, nodeId uint8) (bool, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeBeamingDevice(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// answer true if the node routes messages for other nodes
+func (a *api) IsNodeRoutingDevice(homeId uint32, nodeId uint8) (bool, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeRoutingDevice(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// answer true if the node supports secure communication
+func (a *api) IsNodeSecurityDevice(homeId uint32, nodeId uint8) (bool, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeSecurityDevice(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// get the basic device class of a node, for example 0x04 for a routing slave
+func (a *api) GetNodeBasicType(homeId uint32, nodeId uint8) (uint8, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return 0, err
+	}
+	return (uint8)(C.getNodeBasic(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// get the generic device class of a node, for example 0x10 for a binary switch
+func (a *api) GetNodeGenericType(homeId uint32, nodeId uint8) (uint8, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return 0, err
+	}
+	return (uint8)(C.getNodeGeneric(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+// get the specific device class of a node, which refines its generic device class
+func (a *api) GetNodeSpecificType(homeId uint32, nodeId uint8) (uint8, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return 0, err
+	}
+	return (uint8)(C.getNodeSpecific(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+//
+// Get the name of the device class of a node, such as "Multilevel Power Switch".
+//
+// The name is looked up by OpenZWave in the device_classes.xml file of the config path,
+// using the generic and specific device classes of the node.
+//
+// This version of OpenZWave predates the Z-Wave Plus Info command class, so it cannot tell
+// whether a node is a Z-Wave Plus device, nor answer its Z-Wave Plus role, node type or
+// device type. The device classes are the nearest equivalent.
+//
+func (a *api) GetNodeType(homeId uint32, nodeId uint8) (string, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return "", err
+	}
+	return takeString(C.getNodeType(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+//
+// Answer true if the node is awake. Listening nodes are always awake.
+//
+// A sleeping node wakes up periodically, at its wake-up interval, and OpenZWave reports
+// each transition with a NOTIFICATION notification whose code is CODE.AWAKE or CODE.SLEEP,
+// so subscribers can use these to, for example, push configuration while the node is awake.
+//
+func (a *api) IsNodeAwake(homeId uint32, nodeId uint8) (bool, error) {
+	if err := a.checkNodeKnown(homeId, nodeId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isNodeAwake(C.uint32_t(homeId), C.uint8_t(nodeId))), nil
+}
+
+//
+// Get the ids of the values of a node, in ascending order.
+//
+// The ids are those of the network model, which is kept up to date by the VALUE_ADDED and
+// VALUE_REMOVED notifications, so there is no need to collect the notifications to enumerate
+// the values of a node.
+//
+func (a *api) GetNodeValueIDs(homeId uint32, nodeId uint8) ([]uint64, error) {
+	if err := checkManager(); err != nil {
+		return nil, err
+	}
+	a.model.RLock()
+	defer a.model.RUnlock()
+	n, ok := a.lookupNode(homeId, nodeId)
+	if !ok {
+		return nil, unknownNode(homeId, nodeId)
+	}
+	result := []uint64{}
+	for _, class := range n.classes {
+		for _, instance := range class.instances {
+			for _, v := range instance.values {
+				result = append(result, v.id)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+//
+// Get the ids of the values of one instance of a command class of a node, in ascending order.
+//
+// Devices with several endpoints, such as a dual relay, expose the same command class once
+// for each instance, so this selects, for example, the values that control the second relay.
+// The result is empty if the node does not have the instance.
+//
+func (a *api) GetValuesByInstance(homeId uint32, nodeId uint8, commandClassId uint8, instance uint8) ([]uint64, error) {
+	if err := checkManager(); err != nil {
+		return nil, err
+	}
+	a.model.RLock()
+	defer a.model.RUnlock()
+	n, ok := a.lookupNode(homeId, nodeId)
+	if !ok {
+		return nil, unknownNode(homeId, nodeId)
+	}
+	result := []uint64{}
+	if class, ok := n.classes[commandClassId]; ok {
+		if values, ok := class.instances[instance]; ok {
+			for _, v := range values.values {
+				result = append(result, v.id)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
 }
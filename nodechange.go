@@ -0,0 +1,109 @@
+package openzwave
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ninjasphere/go-openzwave/CODE"
+	"github.com/ninjasphere/go-openzwave/NT"
+)
+
+// the time during which the changes of a node are coalesced into one call of the node changed callbacks
+const nodeChangedWindow = 50 * time.Millisecond
+
+type nodeKey struct {
+	homeId uint32
+	nodeId uint8
+}
+
+type nodeChangedCallbacks struct {
+	sync.Mutex
+	list    []func(homeId uint32, nodeId uint8)
+	pending []nodeKey // the nodes that have changed since the callbacks were last called, in order
+}
+
+//
+// Register a function that is called with the home id and node id of each node that changes,
+// so that a cache entry can be invalidated or a view of the node redrawn without decoding
+// each kind of notification.
+//
+// A node changes when it is added, removed, renamed or (re)queried, when its associations
+// change, when one of its values is added, removed or changed, and when it wakes up, goes to
+// sleep, dies or revives. Changes that arrive in quick succession, such as those for the
+// values of a node that is being queried, are coalesced: the function is called 50
+// milliseconds after the first change, once for each node that changed in that time.
+//
+// The function is called from a goroutine of its own, after the network model has been
+// updated, so it may query the API. It should still return promptly, since the calls for
+// successive changes may otherwise overlap.
+//
+func (a *api) OnNodeChanged(callback func(homeId uint32, nodeId uint8)) {
+	a.nodeChanged.Lock()
+	defer a.nodeChanged.Unlock()
+	a.nodeChanged.list = append(a.nodeChanged.list, callback)
+}
+
+// record the node changed by the notification, if any, and schedule the callbacks if none are scheduled
+func (a *api) notifyNodeChanged(n *notification) {
+	if !isNodeChange(n) {
+		return
+	}
+
+	a.nodeChanged.Lock()
+	defer a.nodeChanged.Unlock()
+	if len(a.nodeChanged.list) == 0 {
+		return
+	}
+	key := nodeKey{n.GetHomeId(), n.GetNodeId()}
+	for _, k := range a.nodeChanged.pending {
+		if k == key {
+			return
+		}
+	}
+	a.nodeChanged.pending = append(a.nodeChanged.pending, key)
+	if len(a.nodeChanged.pending) == 1 {
+		time.AfterFunc(nodeChangedWindow, a.flushNodeChanged)
+	}
+}
+
+// call the node changed callbacks for each node that has changed since they were last called
+func (a *api) flushNodeChanged() {
+	a.nodeChanged.Lock()
+	changed := a.nodeChanged.pending
+	callbacks := append([]func(uint32, uint8){}, a.nodeChanged.list...)
+	a.nodeChanged.pending = nil
+	a.nodeChanged.Unlock()
+
+	for _, key := range changed {
+		for _, callback := range callbacks {
+			callback(key.homeId, key.nodeId)
+		}
+	}
+}
+
+// answer true if the notification reports a change to a node
+func isNodeChange(n Notification) bool {
+	if n.GetNodeId() == 0 {
+		return false
+	}
+	switch n.GetNotificationType().Code {
+	case NT.VALUE_ADDED,
+		NT.VALUE_REMOVED,
+		NT.VALUE_CHANGED,
+		NT.GROUP,
+		NT.NODE_NEW,
+		NT.NODE_ADDED,
+		NT.NODE_REMOVED,
+		NT.NODE_PROTOCOL_INFO,
+		NT.NODE_NAMING,
+		NT.ESSENTIAL_NODE_QUERIES_COMPLETE,
+		NT.NODE_QUERIES_COMPLETE:
+		return true
+	case NT.NOTIFICATION:
+		switch n.GetNotificationCode().Code {
+		case CODE.AWAKE, CODE.SLEEP, CODE.DEAD, CODE.ALIVE:
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,10 @@ static Notification * newNotification(uint8_t notificationType)
 Notification * exportNotification(API * api, OpenZWave::Notification const* notification)
 {
   Notification * result = newNotification(notification->GetType());
+  result->homeId = notification->GetHomeId();
+  result->nodeId = notification->GetNodeId();
+  result->valueId = notification->GetValueID().GetId();
+  result->byte = notification->GetByte();
   result->node = exportNode(api, notification->GetHomeId(), notification->GetNodeId());
   result->notificationCode =
     notification->GetType() == OpenZWave::Notification::Type_Notification
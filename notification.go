@@ -9,14 +9,45 @@ import "C"
 import (
 	"fmt"
 
+	"github.com/ninjasphere/go-openzwave/CC"
 	"github.com/ninjasphere/go-openzwave/CODE"
 	"github.com/ninjasphere/go-openzwave/NT"
+	"github.com/ninjasphere/go-openzwave/VG"
+	"github.com/ninjasphere/go-openzwave/VT"
 )
 
-// The type of notifications received from the API
+// The type of notifications received from the API.
+//
+// The accessors read from the underlying C structure, so they may only be
+// called while the notification is live, that is, for the duration of the
+// NotificationCallback that received it.
 type Notification interface {
 	GetNode() Node
+	GetValue() Value
 	GetNotificationType() *NT.Enum
+	GetNotificationCode() *CODE.Enum
+
+	// the identifier of the home network the notification relates to
+	GetHomeId() uint32
+	// the identifier of the node the notification relates to
+	GetNodeId() uint8
+	// the 64-bit identifier of the value the notification relates to, if any
+	GetValueId() uint64
+	// the genre (basic, user, config or system) of the value the notification relates to
+	GetValueGenre() *VG.Enum
+	// the command class of the value the notification relates to
+	GetCommandClass() *CC.Enum
+	// the instance of the value the notification relates to. Multi-instance devices, such as a
+	// dual relay, report each instance of a command class as a separate value, numbered from 1.
+	GetInstance() uint8
+	// the index of the value the notification relates to, within its command class and instance
+	GetValueIndex() uint8
+	// the event value of a NODE_EVENT notification, for example, the Basic Set value sent by a
+	// scene controller. 0 for other notification types.
+	GetEvent() uint8
+	// the raw byte carried by the notification. This is the group index, event value, button id,
+	// scene id or notification code, depending on the notification type.
+	GetByte() uint8
 }
 
 // The type of notifications received via the API's Notifications() channel.
@@ -31,21 +62,38 @@ func (n *notification) String() string {
 	return fmt.Sprintf(
 		"Notification["+
 			"notificationType=%v/%v, "+
+			"commandClass=%v, "+
+			"instance=%d, "+
+			"index=%d, "+
 			"node=%v, "+
 			"value=%v]",
 		NT.ToEnum(int(n.cRef.notificationType)),
 		CODE.ToEnum(int(n.cRef.notificationCode)),
+		n.GetCommandClass(),
+		instanceOf(n.GetValueId()),
+		indexOf(n.GetValueId()),
 		n.GetNode(),
 		n.GetValue())
 }
 
+//
+// Release the C structures of the notification.
+//
+// The references are cleared as they are released, so a second call has no effect
+// rather than freeing the same C structure twice.
+//
 func (n *notification) free() {
-	C.freeNotification(n.cRef)
+	if n.cRef != nil {
+		C.freeNotification(n.cRef)
+		n.cRef = nil
+	}
 	if n.node != nil {
 		n.node.free()
+		n.node = nil
 	}
 	if n.value != nil {
 		n.value.free()
+		n.value = nil
 	}
 }
 
@@ -61,6 +109,66 @@ func (n *notification) GetNotificationType() *NT.Enum {
 	return NT.ToEnum(int(n.cRef.notificationType))
 }
 
+func (n *notification) GetNotificationCode() *CODE.Enum {
+	return CODE.ToEnum(int(n.cRef.notificationCode))
+}
+
+func (n *notification) GetHomeId() uint32 {
+	return uint32(n.cRef.homeId)
+}
+
+func (n *notification) GetNodeId() uint8 {
+	return uint8(n.cRef.nodeId)
+}
+
+func (n *notification) GetValueId() uint64 {
+	return uint64(n.cRef.valueId)
+}
+
+func (n *notification) GetValueGenre() *VG.Enum {
+	return valueGenreOf(n.GetValueId())
+}
+
+func (n *notification) GetCommandClass() *CC.Enum {
+	return commandClassOf(n.GetValueId())
+}
+
+func (n *notification) GetInstance() uint8 {
+	return instanceOf(n.GetValueId())
+}
+
+func (n *notification) GetValueIndex() uint8 {
+	return indexOf(n.GetValueId())
+}
+
+func (n *notification) GetEvent() uint8 {
+	return eventOf(int(n.cRef.notificationType), uint8(n.cRef.byte))
+}
+
+func (n *notification) GetByte() uint8 {
+	return uint8(n.cRef.byte)
+}
+
+// the event value of a notification, which is only defined for NODE_EVENT notifications
+func eventOf(notificationType int, b uint8) uint8 {
+	if notificationType == NT.NODE_EVENT {
+		return b
+	}
+	return 0
+}
+
+//
+// Allocate a notification with an empty node and value, in the way exportNotification does,
+// so that the handling of its C structures can be exercised without a manager.
+//
+func newEmptyNotification(notificationType int) *notification {
+	cRef := (*C.Notification)(C.calloc(1, C.sizeof_Notification))
+	cRef.notificationType = C.uint8_t(notificationType)
+	cRef.node = (*C.Node)(C.calloc(1, C.sizeof_Node))
+	cRef.value = (*C.Value)(C.calloc(1, C.sizeof_Value))
+	return newGoNotification(cRef)
+}
+
 func newGoNotification(cRef *C.Notification) *notification {
 	result := &notification{cRef, newGoNode(cRef.node), newGoValue(cRef.value)}
 
@@ -113,6 +221,132 @@ func (n *notification) swapValueImpl(existing *value) *value {
 	return existing
 }
 
+//
+// A copy of a notification that does not depend on the underlying C structure, so that
+// it remains valid after the NotificationCallback has returned.
+//
+// The node and value are nil, since those of the network model are changed by the
+// notification thread while the copy is in use.
+//
+type detachedNotification struct {
+	notificationType int
+	notificationCode int
+	homeId           uint32
+	nodeId           uint8
+	valueId          uint64
+	byte             uint8
+}
+
+// copy the notification, keeping only its plain Go data
+func detachNotification(n *notification) *detachedNotification {
+	return &detachedNotification{
+		notificationType: int(n.cRef.notificationType),
+		notificationCode: int(n.cRef.notificationCode),
+		homeId:           n.GetHomeId(),
+		nodeId:           n.GetNodeId(),
+		valueId:          n.GetValueId(),
+		byte:             n.GetByte(),
+	}
+}
+
+//
+// Create a notification that is not backed by OpenZWave, for testing notification handlers.
+//
+// The type, code and value type are the enums answered by the accessors of a notification,
+// for example NT.ToEnum(NT.VALUE_CHANGED), so that a notification received from the API can
+// be copied. The code may be nil for types other than NT.NOTIFICATION, which OpenZWave sends
+// without a code. The value type, such as VT.ToEnum(VT.BOOL), replaces the type encoded in
+// the value id, unless it is nil. The node and value of the notification are nil.
+//
+func NewNotification(homeId uint32, nodeId uint8, notificationType *NT.Enum, notificationCode *CODE.Enum, valueId uint64, valueType *VT.Enum) Notification {
+	result := &detachedNotification{
+		notificationType: notificationType.Code,
+		notificationCode: 0xff, // as the C layer reports a missing code
+		homeId:           homeId,
+		nodeId:           nodeId,
+		valueId:          valueId,
+	}
+	if notificationCode != nil {
+		result.notificationCode = notificationCode.Code
+	}
+	if valueType != nil {
+		result.valueId = valueId&^0x0f | uint64(valueType.Code)&0x0f
+	}
+	return result
+}
+
+func (n *detachedNotification) String() string {
+	return fmt.Sprintf(
+		"Notification["+
+			"notificationType=%v/%v, "+
+			"homeId=0x%08x, "+
+			"nodeId=%03d, "+
+			"valueId=0x%016x, "+
+			"commandClass=%v, "+
+			"instance=%d, "+
+			"index=%d]",
+		n.GetNotificationType(),
+		n.GetNotificationCode(),
+		n.homeId,
+		n.nodeId,
+		n.valueId,
+		n.GetCommandClass(),
+		instanceOf(n.valueId),
+		indexOf(n.valueId))
+}
+
+func (n *detachedNotification) GetNode() Node {
+	return nil
+}
+
+func (n *detachedNotification) GetValue() Value {
+	return nil
+}
+
+func (n *detachedNotification) GetNotificationType() *NT.Enum {
+	return NT.ToEnum(n.notificationType)
+}
+
+func (n *detachedNotification) GetNotificationCode() *CODE.Enum {
+	return CODE.ToEnum(n.notificationCode)
+}
+
+func (n *detachedNotification) GetHomeId() uint32 {
+	return n.homeId
+}
+
+func (n *detachedNotification) GetNodeId() uint8 {
+	return n.nodeId
+}
+
+func (n *detachedNotification) GetValueId() uint64 {
+	return n.valueId
+}
+
+func (n *detachedNotification) GetValueGenre() *VG.Enum {
+	return valueGenreOf(n.valueId)
+}
+
+func (n *detachedNotification) GetCommandClass() *CC.Enum {
+	return commandClassOf(n.valueId)
+}
+
+func (n *detachedNotification) GetInstance() uint8 {
+	return instanceOf(n.valueId)
+}
+
+func (n *detachedNotification) GetValueIndex() uint8 {
+	return indexOf(n.valueId)
+}
+
+func (n *detachedNotification) GetEvent() uint8 {
+	return eventOf(n.notificationType, n.byte)
+}
+
+func (n *detachedNotification) GetByte() uint8 {
+	return n.byte
+}
+
 //
 // called for unexpected notifications.
 //
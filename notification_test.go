@@ -0,0 +1,23 @@
+package openzwave
+
+import (
+	"testing"
+
+	"github.com/ninjasphere/go-openzwave/NT"
+)
+
+func TestFreeNotificationTwice(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("freeing a notification twice panicked: %v", r)
+		}
+	}()
+
+	n := newEmptyNotification(NT.VALUE_CHANGED)
+	n.free()
+	n.free()
+
+	if n.cRef != nil || n.node != nil || n.value != nil {
+		t.Fatal("expected the references of a freed notification to be cleared")
+	}
+}
@@ -24,3 +24,29 @@ void endOptions()
 {
   OpenZWave::Options::Get()->Lock();
 }
+
+bool getIntOption(char * option, int * value)
+{
+  int32 tmp;
+  if (OpenZWave::Options::Get()->GetOptionAsInt(option, &tmp)) {
+    *value = tmp;
+    return true;
+  }
+  return false;
+}
+
+bool getBoolOption(char * option, bool * value)
+{
+  return OpenZWave::Options::Get()->GetOptionAsBool(option, value);
+}
+
+bool getStringOption(char * option, char ** value)
+{
+  std::string tmp;
+  if (OpenZWave::Options::Get()->GetOptionAsString(option, &tmp)) {
+    *value = strdup(tmp.c_str());
+    return true;
+  }
+  *value = NULL;
+  return false;
+}
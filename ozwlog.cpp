@@ -0,0 +1,48 @@
+#include "api.h"
+#include <stdarg.h>
+#include <stdio.h>
+
+//
+// A logging implementation that forwards each message written by OpenZWave to the Go layer,
+// in place of the default implementation that writes the log file and the console.
+//
+// Messages less severe than the SaveLogLevel option are discarded, as they would be by the
+// default implementation.
+//
+class GoLogImpl : public OpenZWave::i_LogImpl
+{
+public:
+  GoLogImpl(API * api, OpenZWave::LogLevel saveLevel) : m_api(api), m_saveLevel(saveLevel) {}
+
+  virtual void Write(OpenZWave::LogLevel level, uint8 const nodeId, char const* format, va_list args)
+  {
+    if (level > m_saveLevel && level != OpenZWave::LogLevel_Internal) {
+      return;
+    }
+    char buffer[1024];
+    vsnprintf(buffer, sizeof(buffer), format, args);
+    onLogWrapper(level, nodeId, buffer, m_api);
+  }
+
+  virtual void QueueDump() {}
+  virtual void QueueClear() {}
+
+  virtual void SetLoggingState(OpenZWave::LogLevel saveLevel, OpenZWave::LogLevel queueLevel, OpenZWave::LogLevel dumpTrigger)
+  {
+    m_saveLevel = saveLevel;
+  }
+
+  virtual void SetLogFileName(const std::string &filename) {}
+
+private:
+  API * m_api;
+  OpenZWave::LogLevel m_saveLevel;
+};
+
+// replaces the logging implementation created by the manager. Must be called after startManager.
+void captureLog(API * api)
+{
+  int32 saveLevel = OpenZWave::LogLevel_Detail;
+  OpenZWave::Options::Get()->GetOptionAsInt("SaveLogLevel", &saveLevel);
+  OpenZWave::Log::SetLoggingClass(new GoLogImpl(api, (OpenZWave::LogLevel)saveLevel));
+}
@@ -0,0 +1,36 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/ninjasphere/go-openzwave/LOG_LEVEL"
+)
+
+//
+// The type of callback that receives the messages logged by OpenZWave itself.
+//
+// Messages that relate to a node are prefixed with the node id, as they are in the log
+// file. The callback is invoked synchronously by OpenZWave's threads while its log is
+// locked, so it MUST NOT block or call back into the API.
+//
+type OZWLogHandler func(level *LOG_LEVEL.Enum, message string)
+
+//export onLogWrapper
+func onLogWrapper(level C.uint8_t, nodeId C.uint8_t, message *C.char, context unsafe.Pointer) {
+	a := (*api)(context)
+	if a.ozwLogHandler == nil {
+		return
+	}
+	text := C.GoString(message)
+	if nodeId != 0 {
+		text = fmt.Sprintf("Node%03d, %s", uint8(nodeId), text)
+	}
+	a.ozwLogHandler(LOG_LEVEL.ToEnum(int(level)), text)
+}
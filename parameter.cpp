@@ -0,0 +1,16 @@
+#include "api.h"
+
+bool setConfigParam(uint32_t homeId, uint8_t nodeId, uint8_t param, int32_t value, uint8_t size)
+{
+  return OpenZWave::Manager::Get()->SetConfigParam(homeId, nodeId, param, value, size);
+}
+
+void requestConfigParam(uint32_t homeId, uint8_t nodeId, uint8_t param)
+{
+  OpenZWave::Manager::Get()->RequestConfigParam(homeId, nodeId, param);
+}
+
+void requestAllConfigParams(uint32_t homeId, uint8_t nodeId)
+{
+  OpenZWave::Manager::Get()->RequestAllConfigParams(homeId, nodeId);
+}
@@ -0,0 +1,46 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+import "fmt"
+
+//
+// Set the value of a configuration parameter of a device, without first locating its value.
+//
+// size is the number of bytes used to encode the value on the wire and must be 1, 2 or 4.
+// Answers the result reported by OpenZWave, which is true if the request was sent.
+// The new value is reported by a VALUE_CHANGED notification once the device confirms it.
+//
+func (a *api) SetConfigParam(homeId uint32, nodeId uint8, param uint8, value int32, size uint8) (bool, error) {
+	if err := checkManager(); err != nil {
+		return false, err
+	}
+	switch size {
+	case 1, 2, 4:
+	default:
+		return false, fmt.Errorf("invalid size %d for configuration parameter %d - must be 1, 2 or 4", size, param)
+	}
+	return (bool)(C.setConfigParam(C.uint32_t(homeId), C.uint8_t(nodeId), C.uint8_t(param), C.int32_t(value), C.uint8_t(size))), nil
+}
+
+// request the value of a configuration parameter of a device. The value is reported by a VALUE_CHANGED notification.
+func (a *api) RequestConfigParam(homeId uint32, nodeId uint8, param uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	C.requestConfigParam(C.uint32_t(homeId), C.uint8_t(nodeId), C.uint8_t(param))
+	return nil
+}
+
+// request the values of all the configuration parameters of a device. Each value is reported by a VALUE_CHANGED notification.
+func (a *api) RequestAllConfigParams(homeId uint32, nodeId uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	C.requestAllConfigParams(C.uint32_t(homeId), C.uint8_t(nodeId))
+	return nil
+}
@@ -0,0 +1,136 @@
+package openzwave
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ninjasphere/go-openzwave/CODE"
+	"github.com/ninjasphere/go-openzwave/NT"
+)
+
+//
+// A value that is waiting to be written to a sleeping node.
+//
+// The type of Value determines which setter is used to write it: bool, uint8, int16,
+// int32, string, float64 (for decimal values) or []byte (for raw values).
+//
+type PendingWrite struct {
+	HomeId  uint32
+	ValueId uint64
+	Value   interface{}
+}
+
+type pendingWrites struct {
+	sync.Mutex
+	list []PendingWrite
+}
+
+//
+// Set a value once the node that owns it is awake.
+//
+// If the node is awake, the value is set immediately. Otherwise, the write is queued and
+// performed when OpenZWave reports that the node has woken up (CODE.AWAKE). A later write
+// to the same value replaces the queued one. Queued writes are discarded if the node is
+// removed, and are not persisted, so they are lost when the process exits.
+//
+func (a *api) QueueSetValueOnWake(homeId uint32, valueId uint64, value interface{}) error {
+	if err := checkValueSetter(valueId, value); err != nil {
+		return err
+	}
+	nodeId := nodeIdOf(valueId)
+	if awake, err := a.IsNodeAwake(homeId, nodeId); err != nil {
+		return err
+	} else if awake {
+		return a.setAnyValue(homeId, valueId, value)
+	}
+
+	a.pendingWrites.Lock()
+	defer a.pendingWrites.Unlock()
+	for i, w := range a.pendingWrites.list {
+		if w.HomeId == homeId && w.ValueId == valueId {
+			a.pendingWrites.list[i].Value = value
+			return nil
+		}
+	}
+	a.pendingWrites.list = append(a.pendingWrites.list, PendingWrite{homeId, valueId, value})
+	return nil
+}
+
+// answer the writes that are queued for a node, in the order they will be performed
+func (a *api) PendingWrites(homeId uint32, nodeId uint8) []PendingWrite {
+	a.pendingWrites.Lock()
+	defer a.pendingWrites.Unlock()
+	result := []PendingWrite{}
+	for _, w := range a.pendingWrites.list {
+		if w.HomeId == homeId && nodeIdOf(w.ValueId) == nodeId {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// remove and answer the writes that are queued for a node
+func (a *api) takePendingWrites(homeId uint32, nodeId uint8) []PendingWrite {
+	a.pendingWrites.Lock()
+	defer a.pendingWrites.Unlock()
+	taken := []PendingWrite{}
+	kept := a.pendingWrites.list[:0]
+	for _, w := range a.pendingWrites.list {
+		if w.HomeId == homeId && nodeIdOf(w.ValueId) == nodeId {
+			taken = append(taken, w)
+		} else {
+			kept = append(kept, w)
+		}
+	}
+	a.pendingWrites.list = kept
+	return taken
+}
+
+// perform the writes queued for a node that has woken up, or discard those of a node that has been removed
+func (a *api) flushPendingWrites(n *notification) {
+	switch n.GetNotificationType().Code {
+	case NT.NOTIFICATION:
+		if n.GetNotificationCode().Code != CODE.AWAKE {
+			return
+		}
+		for _, w := range a.takePendingWrites(n.GetHomeId(), n.GetNodeId()) {
+			if err := a.setAnyValue(w.HomeId, w.ValueId, w.Value); err != nil {
+				a.logger.Warningf("failed to write queued value 0x%016x of node %d in network 0x%08x: %v\n", w.ValueId, n.GetNodeId(), w.HomeId, err)
+			}
+		}
+	case NT.NODE_REMOVED:
+		a.takePendingWrites(n.GetHomeId(), n.GetNodeId())
+	}
+}
+
+// answer an error if there is no setter for the type of the value
+func checkValueSetter(valueId uint64, value interface{}) error {
+	switch value.(type) {
+	case bool, uint8, int16, int32, string, float64, []byte:
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot set value 0x%016x to a value of type %T", ErrValueTypeMismatch, valueId, value)
+	}
+}
+
+// set a value with the setter that matches the type of the value
+func (a *api) setAnyValue(homeId uint32, valueId uint64, value interface{}) error {
+	switch v := value.(type) {
+	case bool:
+		return a.SetBoolValue(homeId, valueId, v)
+	case uint8:
+		return a.SetByteValue(homeId, valueId, v)
+	case int16:
+		return a.SetShortValue(homeId, valueId, v)
+	case int32:
+		return a.SetIntValue(homeId, valueId, v)
+	case string:
+		return a.SetStringValue(homeId, valueId, v)
+	case float64:
+		return a.SetDecimalValue(homeId, valueId, v)
+	case []byte:
+		return a.SetRawValue(homeId, valueId, v)
+	default:
+		return checkValueSetter(valueId, value)
+	}
+}
@@ -0,0 +1,21 @@
+#include "api.h"
+
+bool enablePoll(uint32_t homeId, uint64_t id, uint8_t intensity)
+{
+  return OpenZWave::Manager::Get()->EnablePoll(OpenZWave::ValueID(homeId, id), intensity);
+}
+
+bool disablePoll(uint32_t homeId, uint64_t id)
+{
+  return OpenZWave::Manager::Get()->DisablePoll(OpenZWave::ValueID(homeId, id));
+}
+
+void setPollInterval(int32_t milliseconds, bool intervalBetweenPolls)
+{
+  OpenZWave::Manager::Get()->SetPollInterval(milliseconds, intervalBetweenPolls);
+}
+
+bool isPolled(uint32_t homeId, uint64_t id)
+{
+  return OpenZWave::Manager::Get()->isPolled(OpenZWave::ValueID(homeId, id));
+}
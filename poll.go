@@ -0,0 +1,58 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+import "fmt"
+
+//
+// Enable polling of a value.
+//
+// The intensity is the number of poll cycles between each read of the value, so an intensity
+// of 1 reads the value on every cycle. Answers true if polling was enabled.
+//
+func (a *api) EnablePoll(homeId uint32, valueId uint64, intensity uint8) (bool, error) {
+	if err := checkManager(); err != nil {
+		return false, err
+	}
+	if intensity == 0 {
+		return false, fmt.Errorf("invalid poll intensity 0 for value 0x%016x", valueId)
+	}
+	return (bool)(C.enablePoll(C.uint32_t(homeId), C.uint64_t(valueId), C.uint8_t(intensity))), nil
+}
+
+// disable polling of a value. Answers true if polling was disabled.
+func (a *api) DisablePoll(homeId uint32, valueId uint64) (bool, error) {
+	if err := checkManager(); err != nil {
+		return false, err
+	}
+	return (bool)(C.disablePoll(C.uint32_t(homeId), C.uint64_t(valueId))), nil
+}
+
+//
+// Set the time period between polls.
+//
+// If intervalBetweenPolls is true, the period is the time between the poll of each value,
+// otherwise it is the time taken to poll every polled value once.
+//
+func (a *api) SetPollInterval(milliseconds int32, intervalBetweenPolls bool) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if milliseconds < 0 {
+		return fmt.Errorf("invalid poll interval %dms", milliseconds)
+	}
+	C.setPollInterval(C.int32_t(milliseconds), C._Bool(intervalBetweenPolls))
+	return nil
+}
+
+// answer true if the value is being polled
+func (a *api) IsPolled(homeId uint32, valueId uint64) (bool, error) {
+	if err := a.checkValueKnown(homeId, valueId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isPolled(C.uint32_t(homeId), C.uint64_t(valueId))), nil
+}
@@ -0,0 +1,99 @@
+package openzwave
+
+import (
+	"github.com/ninjasphere/go-openzwave/NT"
+)
+
+//
+// A NotificationRouter dispatches each notification to the callback registered for
+// its notification type, so that consumers do not have to switch on the type themselves.
+//
+// Notifications for which no callback has been registered are passed to the default
+// callback, if any. The router's Handle method is itself a NotificationCallback, so a
+// router can be installed with:
+//
+//	router := openzwave.NewNotificationRouter().
+//	    OnValueChanged(valueChanged).
+//	    OnNodeAdded(nodeAdded)
+//
+//	openzwave.BuildAPI(...).SetNotificationCallback(router.Handle)
+//
+// Callbacks should be registered before the router is installed, since registration is
+// not synchronized with the handling of notifications.
+//
+type NotificationRouter struct {
+	callbacks       map[int]NotificationCallback
+	defaultCallback NotificationCallback
+}
+
+// create a router with no registered callbacks
+func NewNotificationRouter() *NotificationRouter {
+	return &NotificationRouter{make(map[int]NotificationCallback), nil}
+}
+
+// register the callback for the specified notification type, for example NT.GROUP
+func (r *NotificationRouter) On(notificationType int, callback NotificationCallback) *NotificationRouter {
+	r.callbacks[notificationType] = callback
+	return r
+}
+
+// register the callback for notifications that have no other callback registered
+func (r *NotificationRouter) OnDefault(callback NotificationCallback) *NotificationRouter {
+	r.defaultCallback = callback
+	return r
+}
+
+func (r *NotificationRouter) OnValueAdded(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.VALUE_ADDED, callback)
+}
+
+func (r *NotificationRouter) OnValueRemoved(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.VALUE_REMOVED, callback)
+}
+
+func (r *NotificationRouter) OnValueChanged(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.VALUE_CHANGED, callback)
+}
+
+func (r *NotificationRouter) OnValueRefreshed(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.VALUE_REFRESHED, callback)
+}
+
+func (r *NotificationRouter) OnNodeNew(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.NODE_NEW, callback)
+}
+
+func (r *NotificationRouter) OnNodeAdded(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.NODE_ADDED, callback)
+}
+
+func (r *NotificationRouter) OnNodeRemoved(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.NODE_REMOVED, callback)
+}
+
+func (r *NotificationRouter) OnNodeEvent(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.NODE_EVENT, callback)
+}
+
+func (r *NotificationRouter) OnNodeQueriesComplete(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.NODE_QUERIES_COMPLETE, callback)
+}
+
+func (r *NotificationRouter) OnDriverReady(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.DRIVER_READY, callback)
+}
+
+func (r *NotificationRouter) OnDriverFailed(callback NotificationCallback) *NotificationRouter {
+	return r.On(NT.DRIVER_FAILED, callback)
+}
+
+// dispatch the notification to the callback registered for its type, or to the default callback
+func (r *NotificationRouter) Handle(api API, notification Notification) {
+	callback, ok := r.callbacks[notification.GetNotificationType().Code]
+	if !ok {
+		callback = r.defaultCallback
+	}
+	if callback != nil {
+		callback(api, notification)
+	}
+}
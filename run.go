@@ -6,11 +6,18 @@ package openzwave
 import "C"
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"reflect"
+	"runtime/debug"
+	"sync"
 	"time"
 	"unsafe"
+
+	"github.com/ninjasphere/go-openzwave/NT"
 )
 
 const (
@@ -19,6 +26,29 @@ const (
 	EXIT_INTERRUPTED_AGAIN = 125 // something interrupted the current process (twice)
 	EXIT_INTERRUPT_FAILED  = 124 // something interrupted the current process, but something took too long to clean up
 	EXIT_NODE_REMOVED      = 123
+	EXIT_NO_DEVICE         = 122 // the device does not exist, and SetDeviceMustExist(true) was specified
+)
+
+// the time RestartDriver waits for the driver to become ready again
+const restartDriverTimeout = 30 * time.Second
+
+// the time Close waits for the driver to be removed and the manager to be stopped
+const closeTimeout = 10 * time.Second
+
+// The errors returned by RunE for each of the abnormal exit codes.
+var (
+	ErrQuitFailed       = errors.New("failed to remove the driver - the event loop did not exit")
+	ErrInterrupted      = errors.New("interrupted by a signal")
+	ErrInterruptedAgain = errors.New("interrupted by a second signal while shutting down")
+	ErrInterruptFailed  = errors.New("interrupted by a signal, but timed out while waiting for the event loop to quit")
+	ErrNoDevice         = errors.New("the device does not exist")
+	exitErrors          = map[int]error{
+		EXIT_QUIT_FAILED:       ErrQuitFailed,
+		EXIT_INTERRUPTED:       ErrInterrupted,
+		EXIT_INTERRUPTED_AGAIN: ErrInterruptedAgain,
+		EXIT_INTERRUPT_FAILED:  ErrInterruptFailed,
+		EXIT_NO_DEVICE:         ErrNoDevice,
+	}
 )
 
 var defaultEventLoop = func(api API) int {
@@ -46,41 +76,95 @@ var defaultEventCallback = func(api API, event Event) {
 // caused the exit to occur.
 //
 func (a *api) Run() int {
+	return a.RunContext(context.Background())
+}
+
+//
+// Run the supplied event loop until the context is cancelled.
+//
+// Cancellation of the context follows the same graceful driver removal path as an
+// OS interrupt, except that the resulting exit code is 0. OS signals are still handled.
+// RunContext may return before the manager has finished stopping. Once it has stopped, which
+// Close waits for, the API may be run again, for example by a supervisor that restarts it.
+//
+func (a *api) RunContext(ctx context.Context) int {
+
+	if a.simulator != nil {
+		return a.runSimulator(ctx)
+	}
+
+	// fail now, rather than wait for a device that is expected to exist already
+
+	if a.deviceMustExist && !deviceExists(a.device) {
+		a.logger.Errorf("device %s does not exist\n", a.device)
+		return EXIT_NO_DEVICE
+	}
 
 	// lock the options object, now we are done configuring it
 
 	C.endOptions()
 
+	// each run stops its own manager, so that the API can be run again once it has returned
+
+	managerStopped := make(chan struct{})
+	a.managerStoppedLock.Lock()
+	a.managerStopped = managerStopped
+	a.managerStoppedLock.Unlock()
+	a.openSubscriptions()
+
+	// discard the shutdown signals that a previous run left unread
+
+	drainExitCodes(a.shutdownDriver)
+	drainExitCodes(a.quitDeviceMonitor)
+
 	// allocate various channels we need
 
 	signals := make(chan os.Signal, 1) // used to receive OS signals
 	exit := make(chan int, 1)          // used to indicate we are ready to exit
+	stopped := make(chan struct{})     // closed when we return
+
+	defer close(stopped)
 
 	// indicate that we want to wait for these signals
 
 	signal.Notify(signals, os.Interrupt, os.Kill)
+	defer signal.Stop(signals)
+
 	go func() {
-		// Block until a signal is received.
+		// Block until a signal is received or the context is cancelled.
 
-		signal := <-signals
-		// once we receive a signal, exit of the process is inevitable
-		a.logger.Infof("received %v signal - commencing shutdown\n", signal)
+		rc := EXIT_INTERRUPTED
+		select {
+		case signal := <-signals:
+			// once we receive a signal, exit of the process is inevitable
+			a.logger.Infof("received %v signal - commencing shutdown\n", signal)
+		case <-ctx.Done():
+			a.logger.Infof("context cancelled - commencing shutdown\n")
+			rc = 0
+		case <-stopped:
+			return
+		}
 
 		// try a graceful shutdown of the event loop
-		a.shutdownDriver <- EXIT_INTERRUPTED
+		a.shutdownDriver <- rc
 		// and the device monitor loop
-		a.quitDeviceMonitor <- EXIT_INTERRUPTED
+		a.quitDeviceMonitor <- rc
 
 		// but, just in case this doesn't happen, set up an abort timer.
-		time.AfterFunc(time.Second*5, func() {
+		abortTimer := time.AfterFunc(time.Second*5, func() {
 			a.logger.Errorf("timed out while waiting for event loop to quit - aborting now\n")
 			exit <- EXIT_INTERRUPT_FAILED
 		})
 
 		// the user is impatient - just die now
-		signal = <-signals
-		a.logger.Errorf("received 2nd %v signal - aborting now\n", signal)
-		exit <- EXIT_INTERRUPTED_AGAIN
+		select {
+		case signal := <-signals:
+			a.logger.Errorf("received 2nd %v signal - aborting now\n", signal)
+			exit <- EXIT_INTERRUPTED_AGAIN
+		case <-stopped:
+			// the shutdown completed in time
+			abortTimer.Stop()
+		}
 	}()
 
 	//
@@ -111,29 +195,37 @@ func (a *api) Run() int {
 		cSelf := unsafe.Pointer(a) // a reference to a
 
 		C.startManager(cSelf) // start the manager
-		defer C.stopManager(cSelf)
+		defer close(managerStopped)
+		defer a.closeSubscriptions() // after the drain, so that the subscribers receive the last notifications first
+		defer a.stopManager(cSelf)
 
-		cDevice := C.CString(a.device) // allocate a C string for device
-		defer C.free(unsafe.Pointer(cDevice))
+		if a.ozwLogHandler != nil {
+			C.captureLog(cSelf) // replace the log file with the log handler
+		}
 
-		// a function which returns true if the device exists
-		deviceExists := func() bool {
-			if _, err := os.Stat(a.device); err == nil {
-				return true
-			} else {
-				if os.IsNotExist(err) {
-					return false
-				} else {
-					return true
-				}
-			}
+		// monitor each additional device independently, removing their drivers before the manager is stopped
+		quitAdditionalDevices := make(chan struct{})
+		additionalDevices := &sync.WaitGroup{}
+		defer additionalDevices.Wait()
+		defer close(quitAdditionalDevices)
+		for _, device := range a.additionalDevices {
+			additionalDevices.Add(1)
+			go func(device string) {
+				defer additionalDevices.Done()
+				a.monitorDevice(device, quitAdditionalDevices)
+			}(device)
 		}
 
-		// waits until the state matches the desired state.
-		pollUntilDeviceExistsStateEquals := func(comparand bool) {
-			for deviceExists() != comparand {
-				time.Sleep(time.Second)
+		// waits until the state matches the desired state, answering false if the run ended first
+		pollUntilDeviceExistsStateEquals := func(comparand bool) bool {
+			for deviceExists(a.device) != comparand {
+				select {
+				case <-stopped:
+					return false
+				case <-time.After(time.Second):
+				}
 			}
+			return true
 		}
 
 		// there is one iteration of this loop for each device insertion/removal cycle
@@ -148,24 +240,40 @@ func (a *api) Run() int {
 
 				// wait until device present
 				a.logger.Infof("waiting until %s is available\n", a.device)
-				pollUntilDeviceExistsStateEquals(true)
+				for !deviceExists(a.device) && !done {
+					select {
+					case doneExit = <-a.quitDeviceMonitor: // the run ended before the device appeared
+						done = true
+					case <-time.After(time.Second):
+					}
+				}
+				if done {
+					continue
+				}
 				a.logger.Infof("device %s is available\n", a.device)
 
 				go func() {
 
 					// wait until device absent
-					pollUntilDeviceExistsStateEquals(false)
+					if !pollUntilDeviceExistsStateEquals(false) {
+						return
+					}
 					a.logger.Infof("device %s has been removed.\n", a.device)
 
 					// start the removal of the driver
 					a.shutdownDriver <- 0
 				}()
 
-				C.addDriver(cDevice)
+				a.addDriver(a.device)
 
 				go func() {
 					// wait until something (OS signal handler or device existence monitor) decides we need to terminate
-					rc := <-a.shutdownDriver
+					var rc int
+					select {
+					case rc = <-a.shutdownDriver:
+					case <-stopped:
+						return
+					}
 
 					// we start an abort timer, because if the driver blocks, we need to restart the driver process
 					// to guarantee successful operation.
@@ -175,7 +283,7 @@ func (a *api) Run() int {
 					})
 
 					// try to remove the driver
-					if C.removeDriver(cDevice) {
+					if a.removeDriver(a.device) {
 						a.quitEventLoop <- rc
 						abortTimer.Stop() // if we get to here in a timely fashion we can stop the abort timer
 					} else {
@@ -200,6 +308,220 @@ func (a *api) Run() int {
 	return <-exit
 }
 
+// discard the exit codes buffered in the channel
+func drainExitCodes(c chan int) {
+	for {
+		select {
+		case <-c:
+		default:
+			return
+		}
+	}
+}
+
+// answer true if the device exists
+func deviceExists(device string) bool {
+	if _, err := os.Stat(device); err == nil {
+		return true
+	} else {
+		if os.IsNotExist(err) {
+			return false
+		} else {
+			return true
+		}
+	}
+}
+
+//
+// Add and remove the driver of an additional device as the device is inserted and removed.
+//
+// Unlike the device set with SetDeviceName, the removal of an additional device does not
+// stop the event loop. The driver is removed, if necessary, when quit is closed.
+//
+func (a *api) monitorDevice(device string, quit <-chan struct{}) {
+	// waits until the state matches the desired state, answering false if quit was closed first
+	pollUntilDeviceExistsStateEquals := func(comparand bool) bool {
+		for deviceExists(device) != comparand {
+			select {
+			case <-quit:
+				return false
+			case <-time.After(time.Second):
+			}
+		}
+		return true
+	}
+
+	for {
+		if !pollUntilDeviceExistsStateEquals(true) {
+			return
+		}
+		a.logger.Infof("device %s is available\n", device)
+		a.addDriver(device)
+
+		removed := pollUntilDeviceExistsStateEquals(false)
+		if removed {
+			a.logger.Infof("device %s has been removed.\n", device)
+		}
+		if !a.removeDriver(device) {
+			a.logger.Errorf("failed to remove the driver for %s\n", device)
+		}
+		if !removed {
+			return
+		}
+	}
+}
+
+//
+// Run the supplied event loop, but also return an error describing why the loop exited.
+//
+// The error is nil if the event loop exited normally or returned an exit code of its own,
+// otherwise it is one of the Err variables that describes the abnormal exit. The process
+// is never exited - the caller decides whether the exit code should be passed to os.Exit().
+//
+func (a *api) RunE() (int, error) {
+	rc := a.Run()
+	return rc, exitErrors[rc]
+}
+
+//
+// Add the driver for the specified device.
+//
+// The C string for the device name is allocated and freed around each call, rather than
+// being shared by the goroutines of each insertion/removal cycle, so that it can neither
+// leak nor be freed while a removal is still pending. OpenZWave copies the name.
+//
+func (a *api) addDriver(device string) bool {
+	cDevice := C.CString(device)
+	defer C.free(unsafe.Pointer(cDevice))
+	return (bool)(C.addDriver(cDevice))
+}
+
+// Remove the driver for the specified device, then forget the networks it controlled.
+func (a *api) removeDriver(device string) bool {
+	homes := a.homesOfDevice(device)
+	cDevice := C.CString(device)
+	defer C.free(unsafe.Pointer(cDevice))
+	if !(bool)(C.removeDriver(cDevice)) {
+		return false
+	}
+	for _, homeId := range homes {
+		a.driverRemoved(homeId)
+	}
+	return true
+}
+
+// answer the networks whose drivers are ready and are controlled by the device
+func (a *api) homesOfDevice(device string) []uint32 {
+	a.driverReady.Lock()
+	defer a.driverReady.Unlock()
+	homes := []uint32{}
+	for _, homeId := range a.driverReady.homes {
+		if takeString(C.getControllerPath(C.uint32_t(homeId))) == device {
+			homes = append(homes, homeId)
+		}
+	}
+	return homes
+}
+
+// forget a network whose driver has been removed
+func (a *api) driverRemoved(homeId uint32) {
+	a.driverReady.Lock()
+	for i, h := range a.driverReady.homes {
+		if h == homeId {
+			a.driverReady.homes = append(a.driverReady.homes[:i], a.driverReady.homes[i+1:]...)
+			break
+		}
+	}
+	a.driverReady.Unlock()
+
+	// OpenZWave sends no notification once the driver has gone, so the model is reset here
+	a.model.Lock()
+	delete(a.networks, homeId)
+	a.model.Unlock()
+
+	a.closeHomeNotifications(homeId)
+}
+
+//
+// Restart the driver for the current device, without stopping the manager or the event loop.
+//
+// This is useful when the device has re-enumerated. The call blocks until the driver reports
+// that it is ready again, or fails with an error if the driver fails or does not become ready
+// within 30 seconds. It must not be called from a NotificationCallback, since the
+// notification that reports the outcome could then never be delivered.
+//
+func (a *api) RestartDriver() error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+
+	// subscribe before the driver is removed, so that the outcome cannot be missed. Only the
+	// outcome for the networks of the device is awaited, since other devices may have drivers,
+	// unless none of its networks was ready, when any outcome is accepted.
+	outcome := a.subscribeInternal(NotificationFilter{
+		HomeIds:           a.homesOfDevice(a.device),
+		NotificationTypes: []int{NT.DRIVER_READY, NT.DRIVER_FAILED},
+	})
+	defer a.Unsubscribe(outcome)
+
+	if !a.removeDriver(a.device) {
+		return fmt.Errorf("failed to remove the driver for %s", a.device)
+	}
+	if !a.addDriver(a.device) {
+		return fmt.Errorf("failed to add the driver for %s", a.device)
+	}
+
+	select {
+	case nt := <-outcome:
+		if nt.GetNotificationType().Code == NT.DRIVER_FAILED {
+			return fmt.Errorf("the driver for %s failed to restart", a.device)
+		}
+		return nil
+	case <-time.After(restartDriverTimeout):
+		return fmt.Errorf("timed out after %v waiting for the driver for %s to restart", restartDriverTimeout, a.device)
+	}
+}
+
+type driverReadyCallbacks struct {
+	sync.Mutex
+	list  []func(homeId uint32)
+	homes []uint32 // the networks whose drivers have reported DRIVER_READY
+}
+
+//
+// Register a function that is called with the home id of each network whose driver becomes
+// ready, so that work keyed on the home id can start without watching for DRIVER_READY.
+//
+// The function is called immediately for the networks whose drivers are already ready, then
+// once for each DRIVER_READY notification, including those that follow a RestartDriver.
+// Like the NotificationCallback, it is called from OpenZWave's notification thread and MUST
+// NOT block.
+//
+func (a *api) OnDriverReady(callback func(homeId uint32)) {
+	a.driverReady.Lock()
+	defer a.driverReady.Unlock()
+	a.driverReady.list = append(a.driverReady.list, callback)
+	for _, homeId := range a.driverReady.homes {
+		callback(homeId)
+	}
+}
+
+// call the driver ready callbacks if the notification reports that a driver is ready
+func (a *api) notifyDriverReady(n *notification) {
+	if n.GetNotificationType().Code != NT.DRIVER_READY {
+		return
+	}
+	homeId := n.GetHomeId()
+	a.driverReady.Lock()
+	defer a.driverReady.Unlock()
+	if !containsUint32(a.driverReady.homes, homeId) {
+		a.driverReady.homes = append(a.driverReady.homes, homeId)
+	}
+	for _, callback := range a.driverReady.list {
+		callback(homeId)
+	}
+}
+
 func (a *api) Shutdown(exit int) {
 
 	select {
@@ -216,18 +538,111 @@ func (a *api) Shutdown(exit int) {
 
 }
 
+//
+// Remove the driver and stop the manager, then answer once they have stopped, without
+// relying on an OS signal and without exiting the process.
+//
+// This starts the same graceful shutdown as Shutdown(0), so the event loop receives a quit
+// signal and must return, then waits for the manager to stop and the subscription channels
+// to be closed. An error is returned if this takes longer than 10 seconds. Calling Close
+// after the manager has stopped, or before Run, does nothing. Since the event loop must
+// return for the shutdown to complete, Close MUST NOT be called from the event loop, nor
+// from any of the callbacks.
+//
+func (a *api) Close() error {
+	if err := checkManager(); err != nil && a.simulator == nil {
+		return nil
+	}
+	a.managerStoppedLock.Lock()
+	managerStopped := a.managerStopped
+	a.managerStoppedLock.Unlock()
+	if managerStopped == nil {
+		return nil // a simulated network that has not been run
+	}
+
+	a.Shutdown(0)
+	select {
+	case <-managerStopped:
+		return nil
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("timed out after %v waiting for the manager to stop", closeTimeout)
+	}
+}
+
+//
+// Stop the manager, giving the notifications that are still pending up to the drain timeout
+// to reach the subscribers.
+//
+// Stopping the manager removes the notification callback, which waits for the notification
+// being delivered, so the timer releases a delivery that is blocked on a full channel.
+//
+func (a *api) stopManager(cSelf unsafe.Pointer) {
+	release := time.AfterFunc(a.drainTimeout, a.releaseSubscribers)
+	defer release.Stop()
+
+	C.stopManager(cSelf)
+
+	debounced := a.takeDebounced()
+	if a.drainTimeout <= 0 {
+		if len(debounced) > 0 {
+			a.logger.Debugf("discarded %d debounced notifications on shutdown\n", len(debounced))
+		}
+		return
+	}
+	for _, n := range debounced {
+		a.broadcast(n)
+	}
+}
+
 //export onNotificationWrapper
 func onNotificationWrapper(cNotification *C.Notification, context unsafe.Pointer) {
 	// marshal from C to Go
 	a := (*api)(context)
 	goNotification := newGoNotification(cNotification)
 	if a.callback != nil {
-		a.callback(a, goNotification)
+		a.safely("the notification callback", goNotification, func() {
+			a.callback(a, goNotification)
+		})
 	}
 
-	// forward the notification to the network
-	a.getNetwork(goNotification.GetNode().GetHomeId()).notify(a, goNotification)
+	// forward the notification to the network, which reports node events to the EventCallback
+	a.safely("the event callback", goNotification, func() {
+		a.getNetwork(goNotification.GetNode().GetHomeId()).notify(a, goNotification)
+	})
+
+	// report a driver that has become ready
+	a.safely("a driver ready callback", goNotification, func() {
+		a.notifyDriverReady(goNotification)
+	})
+
+	// record the changed value, before the subscribers can ask for it
+	a.updateValueCache(goNotification)
+
+	// schedule the node changed callbacks, once the network has been updated
+	a.notifyNodeChanged(goNotification)
+
+	// and to the subscribers
+	a.safely("the delivery to the subscribers", goNotification, func() {
+		a.publish(goNotification)
+	})
+
+	// then perform the writes queued for a node that has woken up
+	a.flushPendingWrites(goNotification)
 
 	// release the notification
 	goNotification.free()
 }
+
+//
+// Call a function that runs code supplied by the application, such as a callback, and log
+// any panic instead of letting it unwind into OpenZWave's notification thread, which would
+// crash the process. The remaining steps of the handling of the notification still run.
+//
+func (a *api) safely(description string, n Notification, f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			a.logger.Errorf("recovered from a panic in %s while handling %v: %v\n%s", description, n, r, debug.Stack())
+		}
+	}()
+	f()
+}
@@ -0,0 +1,63 @@
+#include "api.h"
+
+uint8_t createScene()
+{
+  return OpenZWave::Manager::Get()->CreateScene();
+}
+
+bool removeScene(uint8_t sceneId)
+{
+  return OpenZWave::Manager::Get()->RemoveScene(sceneId);
+}
+
+// exports the ids of all the scenes into an array that must be released with freeScenes
+uint8_t getAllScenes(uint8_t ** sceneIds)
+{
+  *sceneIds = NULL;
+  return OpenZWave::Manager::Get()->GetAllScenes(sceneIds);
+}
+
+void freeScenes(uint8_t * sceneIds)
+{
+  delete [] sceneIds;
+}
+
+bool sceneExists(uint8_t sceneId)
+{
+  return OpenZWave::Manager::Get()->SceneExists(sceneId);
+}
+
+void setSceneLabel(uint8_t sceneId, char * label)
+{
+  OpenZWave::Manager::Get()->SetSceneLabel(sceneId, std::string(label));
+}
+
+bool addSceneBoolValue(uint8_t sceneId, uint32_t homeId, uint64_t id, bool value)
+{
+  return OpenZWave::Manager::Get()->AddSceneValue(sceneId, OpenZWave::ValueID(homeId, id), value);
+}
+
+bool addSceneIntValue(uint8_t sceneId, uint32_t homeId, uint64_t id, int32_t value)
+{
+  return OpenZWave::Manager::Get()->AddSceneValue(sceneId, OpenZWave::ValueID(homeId, id), value);
+}
+
+bool addSceneUint8Value(uint8_t sceneId, uint32_t homeId, uint64_t id, uint8_t value)
+{
+  return OpenZWave::Manager::Get()->AddSceneValue(sceneId, OpenZWave::ValueID(homeId, id), value);
+}
+
+bool addSceneStringValue(uint8_t sceneId, uint32_t homeId, uint64_t id, char * value)
+{
+  return OpenZWave::Manager::Get()->AddSceneValue(sceneId, OpenZWave::ValueID(homeId, id), std::string(value));
+}
+
+bool removeSceneValue(uint8_t sceneId, uint32_t homeId, uint64_t id)
+{
+  return OpenZWave::Manager::Get()->RemoveSceneValue(sceneId, OpenZWave::ValueID(homeId, id));
+}
+
+bool activateScene(uint8_t sceneId)
+{
+  return OpenZWave::Manager::Get()->ActivateScene(sceneId);
+}
@@ -0,0 +1,147 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+//
+// Scenes are managed by OpenZWave itself rather than by the devices, so they are not
+// associated with any particular network. They are persisted with the rest of the
+// configuration of the network when the configuration is written.
+//
+
+// create a new scene, answering its id
+func (a *api) CreateScene() (uint8, error) {
+	if err := checkManager(); err != nil {
+		return 0, err
+	}
+	sceneId := (uint8)(C.createScene())
+	if sceneId == 0 {
+		return 0, fmt.Errorf("failed to create a scene - too many scenes")
+	}
+	return sceneId, nil
+}
+
+// remove a scene
+func (a *api) RemoveScene(sceneId uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if !(bool)(C.removeScene(C.uint8_t(sceneId))) {
+		return fmt.Errorf("%w %d", ErrUnknownScene, sceneId)
+	}
+	return nil
+}
+
+// get the ids of all the scenes
+func (a *api) GetScenes() ([]uint8, error) {
+	if err := checkManager(); err != nil {
+		return nil, err
+	}
+	var sceneIds *C.uint8_t
+	count := C.getAllScenes(&sceneIds)
+	defer C.freeScenes(sceneIds)
+
+	result := make([]uint8, int(count))
+	for i, sceneId := range unsafe.Slice(sceneIds, int(count)) {
+		result[i] = (uint8)(sceneId)
+	}
+	return result, nil
+}
+
+// answer an error if the manager is not started or the scene does not exist
+func checkScene(sceneId uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if !(bool)(C.sceneExists(C.uint8_t(sceneId))) {
+		return fmt.Errorf("%w %d", ErrUnknownScene, sceneId)
+	}
+	return nil
+}
+
+// set the label of a scene
+func (a *api) SetSceneLabel(sceneId uint8, label string) error {
+	if err := checkScene(sceneId); err != nil {
+		return err
+	}
+	cLabel := C.CString(label)
+	defer C.free(unsafe.Pointer(cLabel))
+	C.setSceneLabel(C.uint8_t(sceneId), cLabel)
+	return nil
+}
+
+// check the scene and the type of the value, then apply the specified function to add the value to the scene
+func addSceneValue(sceneId uint8, valueId uint64, expected int, add func() bool) error {
+	if err := checkScene(sceneId); err != nil {
+		return err
+	}
+	if err := checkValueType(valueId, expected); err != nil {
+		return err
+	}
+	if !add() {
+		return fmt.Errorf("failed to add value 0x%016x to scene %d", valueId, sceneId)
+	}
+	return nil
+}
+
+// add a boolean value to a scene, with the value it is to be set to when the scene is activated
+func (a *api) AddSceneBoolValue(sceneId uint8, homeId uint32, valueId uint64, value bool) error {
+	return addSceneValue(sceneId, valueId, VT.BOOL, func() bool {
+		return (bool)(C.addSceneBoolValue(C.uint8_t(sceneId), C.uint32_t(homeId), C.uint64_t(valueId), C._Bool(value)))
+	})
+}
+
+// add an integer value to a scene, with the value it is to be set to when the scene is activated
+func (a *api) AddSceneIntValue(sceneId uint8, homeId uint32, valueId uint64, value int32) error {
+	return addSceneValue(sceneId, valueId, VT.INT, func() bool {
+		return (bool)(C.addSceneIntValue(C.uint8_t(sceneId), C.uint32_t(homeId), C.uint64_t(valueId), C.int32_t(value)))
+	})
+}
+
+// add a byte value to a scene, with the value it is to be set to when the scene is activated
+func (a *api) AddSceneByteValue(sceneId uint8, homeId uint32, valueId uint64, value uint8) error {
+	return addSceneValue(sceneId, valueId, VT.BYTE, func() bool {
+		return (bool)(C.addSceneUint8Value(C.uint8_t(sceneId), C.uint32_t(homeId), C.uint64_t(valueId), C.uint8_t(value)))
+	})
+}
+
+// add a string value to a scene, with the value it is to be set to when the scene is activated
+func (a *api) AddSceneStringValue(sceneId uint8, homeId uint32, valueId uint64, value string) error {
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+	return addSceneValue(sceneId, valueId, VT.STRING, func() bool {
+		return (bool)(C.addSceneStringValue(C.uint8_t(sceneId), C.uint32_t(homeId), C.uint64_t(valueId), cValue))
+	})
+}
+
+// remove a value from a scene
+func (a *api) RemoveSceneValue(sceneId uint8, homeId uint32, valueId uint64) error {
+	if err := checkScene(sceneId); err != nil {
+		return err
+	}
+	if !(bool)(C.removeSceneValue(C.uint8_t(sceneId), C.uint32_t(homeId), C.uint64_t(valueId))) {
+		return fmt.Errorf("value 0x%016x is not part of scene %d", valueId, sceneId)
+	}
+	return nil
+}
+
+// set each of the values of a scene to the value recorded in the scene
+func (a *api) ActivateScene(sceneId uint8) error {
+	if err := checkScene(sceneId); err != nil {
+		return err
+	}
+	if !(bool)(C.activateScene(C.uint8_t(sceneId))) {
+		return fmt.Errorf("failed to activate scene %d", sceneId)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package openzwave
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+)
+
+//
+// A scripted network, announced as if a controller had just found it by WithSimulator, or
+// by the Simulate method of a mock.MockAPI.
+//
+// A SimConfig is usually loaded from a JSON file with LoadSimConfig, for example:
+//
+//	{
+//		"homeId": 3735928559,
+//		"nodes": [
+//			{"nodeId": 2, "name": "Lamp", "values": [{"valueId": 72057594076282880, "value": false}]}
+//		],
+//		"changes": [
+//			{"delayMs": 1000, "valueId": 72057594076282880, "value": true}
+//		]
+//	}
+//
+// The type of each value is taken from its value id, so JSON numbers become the uint8,
+// int16, int32 or float64 expected by the getters of the simulated API.
+//
+type SimConfig struct {
+	HomeId  uint32      `json:"homeId"`
+	Nodes   []SimNode   `json:"nodes"`
+	Changes []SimChange `json:"changes"`
+}
+
+// A node of a simulated network, and the initial state of its values.
+type SimNode struct {
+	NodeId   uint8      `json:"nodeId"`
+	Name     string     `json:"name"`
+	Location string     `json:"location"`
+	Values   []SimValue `json:"values"`
+}
+
+// A value of a simulated node.
+type SimValue struct {
+	ValueId uint64      `json:"valueId"`
+	Value   interface{} `json:"value"`
+}
+
+// A scripted change of a value, made after a delay that starts when the previous change is made.
+type SimChange struct {
+	DelayMs int         `json:"delayMs"`
+	ValueId uint64      `json:"valueId"`
+	Value   interface{} `json:"value"`
+}
+
+// load a SimConfig from a JSON file
+func LoadSimConfig(path string) (SimConfig, error) {
+	var config SimConfig
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("failed to parse simulator config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// the simulator registered by the mock package, if it has been imported
+var newSimulator func(config SimConfig, logger Logger, callback NotificationCallback) API
+
+var errNoSimulator = errors.New("no simulator is registered - import github.com/ninjasphere/go-openzwave/mock")
+
+//
+// Register the function that creates the API of a simulated network for WithSimulator.
+//
+// The simulator is implemented by the mock package, which registers it when it is imported,
+// so this is only needed by an alternative implementation. The function must announce the
+// network from another goroutine, deliver each notification to the callback, if it is not
+// nil, and answer VALUE_CHANGED notifications for the values that are set.
+//
+func RegisterSimulator(factory func(config SimConfig, logger Logger, callback NotificationCallback) API) {
+	newSimulator = factory
+}
+
+//
+// Run the event loop against a simulated network instead of OpenZWave and a controller.
+//
+// The network described by the config is announced to the NotificationCallback and the
+// subscribers once the event loop first subscribes, or after a second if it does not. Its
+// scripted changes are then made, and each value that is set is announced with a
+// VALUE_CHANGED notification, so that code written against the API runs unchanged while a
+// user interface is developed without a controller. The events callback and the device
+// factory are not called, and the options, paths and devices are not used.
+//
+// The simulator is implemented by the mock package, which must be imported, for example:
+//
+//	import _ "github.com/ninjasphere/go-openzwave/mock"
+//
+func (a *api) WithSimulator(config SimConfig) Configurator {
+	a.simulator = &config
+	return a
+}
+
+// run the event loop against the simulated network until it exits, a signal is received or the context is cancelled
+func (a *api) runSimulator(ctx context.Context) int {
+	if newSimulator == nil {
+		a.logger.Errorf("%v\n", errNoSimulator)
+		return EXIT_NO_DEVICE
+	}
+	simulated := newSimulator(*a.simulator, a.logger, a.callback)
+
+	// Close waits for the simulated network to be closed, as it waits for the manager to stop
+
+	managerStopped := make(chan struct{})
+	a.managerStoppedLock.Lock()
+	a.managerStopped = managerStopped
+	a.managerStoppedLock.Unlock()
+	defer close(managerStopped)
+
+	drainExitCodes(a.shutdownDriver)
+	drainExitCodes(a.quitDeviceMonitor)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, os.Kill)
+	defer signal.Stop(signals)
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		rc := EXIT_INTERRUPTED
+		select {
+		case signal := <-signals:
+			a.logger.Infof("received %v signal - commencing shutdown\n", signal)
+		case <-ctx.Done():
+			a.logger.Infof("context cancelled - commencing shutdown\n")
+			rc = 0
+		case rc = <-a.shutdownDriver: // Shutdown or Close was called
+		case rc = <-a.quitDeviceMonitor:
+		case <-stopped:
+			return
+		}
+		simulated.Shutdown(rc)
+	}()
+
+	rc := a.loop(simulated)
+	simulated.Close() // also stops the scripted changes of the simulated network
+	return rc
+}
@@ -0,0 +1,124 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+import (
+	"sort"
+
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+// A copy of the networks known to the API, suitable for encoding with encoding/json.
+type NetworkSnapshot struct {
+	Homes []HomeSnapshot `json:"homes"`
+}
+
+// A copy of a single network.
+type HomeSnapshot struct {
+	HomeId uint32         `json:"homeId"`
+	Nodes  []NodeSnapshot `json:"nodes"`
+}
+
+// A copy of a single node and its values.
+type NodeSnapshot struct {
+	NodeId           uint8           `json:"nodeId"`
+	Name             string          `json:"name"`
+	Location         string          `json:"location"`
+	NodeType         string          `json:"nodeType"`
+	ManufacturerName string          `json:"manufacturerName"`
+	ManufacturerId   string          `json:"manufacturerId"`
+	ProductName      string          `json:"productName"`
+	ProductType      string          `json:"productType"`
+	ProductId        string          `json:"productId"`
+	Values           []ValueSnapshot `json:"values"`
+}
+
+// A copy of a single value. Value is the current value, formatted as a string.
+type ValueSnapshot struct {
+	ValueId      uint64 `json:"valueId"`
+	CommandClass string `json:"commandClass"`
+	Instance     uint8  `json:"instance"`
+	Index        uint8  `json:"index"`
+	Type         string `json:"type"`
+	Genre        string `json:"genre"`
+	Label        string `json:"label"`
+	Value        string `json:"value"`
+	Units        string `json:"units"`
+	Help         string `json:"help"`
+	IsSet        bool   `json:"isSet"`
+}
+
+//
+// Take a copy of the networks, nodes and values known to the API.
+//
+// The copy is built from the network model maintained from the notifications, so it
+// contains only the nodes whose queries have progressed far enough to be reported.
+// Homes, nodes and values are ordered by their ids. The strings are copied while the model
+// is locked, so the copy does not share any memory with the model.
+//
+func (a *api) Snapshot() (NetworkSnapshot, error) {
+	if err := checkManager(); err != nil {
+		return NetworkSnapshot{}, err
+	}
+
+	a.model.RLock()
+	defer a.model.RUnlock()
+
+	snapshot := NetworkSnapshot{Homes: []HomeSnapshot{}}
+	for _, net := range a.networks {
+		home := HomeSnapshot{HomeId: net.homeId, Nodes: []NodeSnapshot{}}
+		for _, n := range net.nodes {
+			home.Nodes = append(home.Nodes, n.snapshot())
+		}
+		sort.Slice(home.Nodes, func(i, j int) bool { return home.Nodes[i].NodeId < home.Nodes[j].NodeId })
+		snapshot.Homes = append(snapshot.Homes, home)
+	}
+	sort.Slice(snapshot.Homes, func(i, j int) bool { return snapshot.Homes[i].HomeId < snapshot.Homes[j].HomeId })
+	return snapshot, nil
+}
+
+// copy the node and its values. The caller must hold the model lock.
+func (n *node) snapshot() NodeSnapshot {
+	result := NodeSnapshot{
+		NodeId:           n.GetId(),
+		Name:             C.GoString(n.cRef.nodeName),
+		Location:         C.GoString(n.cRef.location),
+		NodeType:         C.GoString(n.cRef.nodeType),
+		ManufacturerName: C.GoString(n.cRef.manufacturerName),
+		ManufacturerId:   C.GoString(n.cRef.manufacturerId),
+		ProductName:      C.GoString(n.cRef.productName),
+		ProductType:      C.GoString(n.cRef.productType),
+		ProductId:        C.GoString(n.cRef.productId),
+		Values:           []ValueSnapshot{},
+	}
+	for _, class := range n.classes {
+		for _, instance := range class.instances {
+			for _, v := range instance.values {
+				result.Values = append(result.Values, v.snapshot())
+			}
+		}
+	}
+	sort.Slice(result.Values, func(i, j int) bool { return result.Values[i].ValueId < result.Values[j].ValueId })
+	return result
+}
+
+func (v *value) snapshot() ValueSnapshot {
+	valueId := uint64(v.cRef.valueId.id)
+	return ValueSnapshot{
+		ValueId:      valueId,
+		CommandClass: commandClassOf(valueId).String(),
+		Instance:     uint8(v.cRef.valueId.instance),
+		Index:        uint8(v.cRef.valueId.index),
+		Type:         VT.ToEnum(int(v.cRef.valueId.valueType)).String(),
+		Genre:        valueGenreOf(valueId).String(),
+		Label:        C.GoString(v.cRef.label),
+		Value:        C.GoString(v.cRef.value),
+		Units:        C.GoString(v.cRef.units),
+		Help:         C.GoString(v.cRef.help),
+		IsSet:        (bool)(v.cRef.isSet),
+	}
+}
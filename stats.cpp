@@ -0,0 +1,47 @@
+#include "api.h"
+
+void getNodeStatistics(uint32_t homeId, uint8_t nodeId, NodeStats * stats)
+{
+  OpenZWave::Node::NodeData data = OpenZWave::Node::NodeData();
+  OpenZWave::Manager::Get()->GetNodeStatistics(homeId, nodeId, &data);
+
+  stats->sentCount = data.m_sentCnt;
+  stats->sentFailed = data.m_sentFailed;
+  stats->retryCount = data.m_retries;
+  stats->receivedCount = data.m_receivedCnt;
+  stats->receivedDuplicates = data.m_receivedDups;
+  stats->receivedUnsolicited = data.m_receivedUnsolicited;
+  stats->lastRequestRTT = data.m_lastRequestRTT;
+  stats->averageRequestRTT = data.m_averageRequestRTT;
+  stats->lastResponseRTT = data.m_lastResponseRTT;
+  stats->averageResponseRTT = data.m_averageResponseRTT;
+  stats->quality = data.m_quality;
+}
+
+void getDriverStatistics(uint32_t homeId, DriverStats * stats)
+{
+  OpenZWave::Driver::DriverData data = OpenZWave::Driver::DriverData();
+  OpenZWave::Manager::Get()->GetDriverStatistics(homeId, &data);
+
+  stats->sofCount = data.m_SOFCnt;
+  stats->ackWaiting = data.m_ACKWaiting;
+  stats->readAborts = data.m_readAborts;
+  stats->badChecksums = data.m_badChecksum;
+  stats->readCount = data.m_readCnt;
+  stats->writeCount = data.m_writeCnt;
+  stats->canCount = data.m_CANCnt;
+  stats->nakCount = data.m_NAKCnt;
+  stats->ackCount = data.m_ACKCnt;
+  stats->oofCount = data.m_OOFCnt;
+  stats->dropped = data.m_dropped;
+  stats->retries = data.m_retries;
+  stats->callbacks = data.m_callbacks;
+  stats->badRoutes = data.m_badroutes;
+  stats->noAck = data.m_noack;
+  stats->netBusy = data.m_netbusy;
+  stats->notIdle = data.m_notidle;
+  stats->nonDelivery = data.m_nondelivery;
+  stats->routedBusy = data.m_routedbusy;
+  stats->broadcastReadCount = data.m_broadcastReadCnt;
+  stats->broadcastWriteCount = data.m_broadcastWriteCnt;
+}
@@ -0,0 +1,101 @@
+package openzwave
+
+// #cgo LDFLAGS: -lopenzwave -Lgo/src/github.com/ninjasphere/go-openzwave/openzwave
+// #cgo CPPFLAGS: -Iopenzwave/cpp/src/platform -Iopenzwave/cpp/src -Iopenzwave/cpp/src/value_classes
+//
+// #include "api.h"
+import "C"
+
+// The message statistics of a node, as recorded by the driver. Round trip times are in milliseconds.
+type NodeStats struct {
+	SentCount           uint32 // messages sent to the node
+	SentFailed          uint32 // messages that could not be sent to the node
+	RetryCount          uint32 // messages that were retried
+	ReceivedPackets     uint32 // messages received from the node
+	ReceivedDuplicates  uint32 // duplicate messages received from the node
+	ReceivedUnsolicited uint32 // unsolicited messages received from the node
+	LastRequestRTT      uint32
+	AverageRequestRTT   uint32
+	LastResponseRTT     uint32
+	AverageResponseRTT  uint32
+	Quality             uint8 // the quality of the link to the node
+}
+
+// The message statistics of a driver, that is, of the serial link to the controller and of the network as a whole.
+type DriverStats struct {
+	SOFCount            uint32 // SOF bytes received
+	ACKWaiting          uint32 // unsolicited messages received while waiting for an ACK
+	ReadAborts          uint32 // reads aborted due to timeouts
+	BadChecksums        uint32 // messages received with bad checksums
+	ReadCount           uint32 // messages successfully read
+	WriteCount          uint32 // messages successfully written
+	CANCount            uint32 // CAN bytes received
+	NAKCount            uint32 // NAK bytes received
+	ACKCount            uint32 // ACK bytes received
+	OOFCount            uint32 // bytes received out of framing
+	Dropped             uint32 // messages dropped and not delivered
+	Retries             uint32 // messages retransmitted
+	Callbacks           uint32 // unexpected callbacks
+	BadRoutes           uint32 // messages that failed due to a bad route response
+	NoACK               uint32 // messages for which no ACK was returned
+	NetBusy             uint32 // network busy or failure messages
+	NotIdle             uint32 // messages that failed because the network was not idle
+	NonDelivery         uint32 // messages not delivered to the network
+	RoutedBusy          uint32 // messages received with routed busy status
+	BroadcastReadCount  uint32 // broadcasts received
+	BroadcastWriteCount uint32 // broadcasts sent
+}
+
+// get the message statistics of a node
+func (a *api) GetNodeStatistics(homeId uint32, nodeId uint8) (NodeStats, error) {
+	if err := checkDriver(homeId); err != nil {
+		return NodeStats{}, err
+	}
+	var stats C.NodeStats
+	C.getNodeStatistics(C.uint32_t(homeId), C.uint8_t(nodeId), &stats)
+	return NodeStats{
+		SentCount:           uint32(stats.sentCount),
+		SentFailed:          uint32(stats.sentFailed),
+		RetryCount:          uint32(stats.retryCount),
+		ReceivedPackets:     uint32(stats.receivedCount),
+		ReceivedDuplicates:  uint32(stats.receivedDuplicates),
+		ReceivedUnsolicited: uint32(stats.receivedUnsolicited),
+		LastRequestRTT:      uint32(stats.lastRequestRTT),
+		AverageRequestRTT:   uint32(stats.averageRequestRTT),
+		LastResponseRTT:     uint32(stats.lastResponseRTT),
+		AverageResponseRTT:  uint32(stats.averageResponseRTT),
+		Quality:             uint8(stats.quality),
+	}, nil
+}
+
+// get the message statistics of the driver of a network
+func (a *api) GetDriverStatistics(homeId uint32) (DriverStats, error) {
+	if err := checkDriver(homeId); err != nil {
+		return DriverStats{}, err
+	}
+	var stats C.DriverStats
+	C.getDriverStatistics(C.uint32_t(homeId), &stats)
+	return DriverStats{
+		SOFCount:            uint32(stats.sofCount),
+		ACKWaiting:          uint32(stats.ackWaiting),
+		ReadAborts:          uint32(stats.readAborts),
+		BadChecksums:        uint32(stats.badChecksums),
+		ReadCount:           uint32(stats.readCount),
+		WriteCount:          uint32(stats.writeCount),
+		CANCount:            uint32(stats.canCount),
+		NAKCount:            uint32(stats.nakCount),
+		ACKCount:            uint32(stats.ackCount),
+		OOFCount:            uint32(stats.oofCount),
+		Dropped:             uint32(stats.dropped),
+		Retries:             uint32(stats.retries),
+		Callbacks:           uint32(stats.callbacks),
+		BadRoutes:           uint32(stats.badRoutes),
+		NoACK:               uint32(stats.noAck),
+		NetBusy:             uint32(stats.netBusy),
+		NotIdle:             uint32(stats.notIdle),
+		NonDelivery:         uint32(stats.nonDelivery),
+		RoutedBusy:          uint32(stats.routedBusy),
+		BroadcastReadCount:  uint32(stats.broadcastReadCount),
+		BroadcastWriteCount: uint32(stats.broadcastWriteCount),
+	}, nil
+}
@@ -0,0 +1,344 @@
+package openzwave
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Determines what happens when a notification is delivered to a subscriber whose channel is full.
+type OverflowPolicy int
+
+const (
+	OVERFLOW_BLOCK       OverflowPolicy = iota // wait until the subscriber receives a notification or unsubscribes
+	OVERFLOW_DROP_NEWEST                       // discard the notification being delivered
+	OVERFLOW_DROP_OLDEST                       // discard the oldest notification in the channel to make room
+)
+
+//
+// Selects the notifications delivered to a subscriber.
+//
+// Each field lists the acceptable values of one attribute of a notification. An empty
+// list accepts any value, so the zero NotificationFilter accepts every notification.
+// A notification is delivered only if it is accepted by every field.
+//
+type NotificationFilter struct {
+	HomeIds           []uint32
+	NodeIds           []uint8
+	CommandClasses    []uint8 // for example, CC.SWITCH_BINARY
+	NotificationTypes []int   // for example, NT.VALUE_CHANGED
+}
+
+// answer true if the notification is accepted by the filter
+func (f *NotificationFilter) Accepts(n Notification) bool {
+	return (len(f.HomeIds) == 0 || containsUint32(f.HomeIds, n.GetHomeId())) &&
+		(len(f.NodeIds) == 0 || containsUint8(f.NodeIds, n.GetNodeId())) &&
+		(len(f.CommandClasses) == 0 || containsUint8(f.CommandClasses, commandClassIdOf(n.GetValueId()))) &&
+		(len(f.NotificationTypes) == 0 || containsInt(f.NotificationTypes, n.GetNotificationType().Code))
+}
+
+func containsUint32(list []uint32, v uint32) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUint8(list []uint8, v uint8) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, v int) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+type subscription struct {
+	filter        NotificationFilter
+	notifications chan Notification
+	done          chan struct{} // closed when the subscriber unsubscribes, or when deliveries must stop blocking
+	once          sync.Once
+	sending       sync.Mutex // held while a notification is delivered, so that the channel is not closed during a send
+	closed        bool       // true once the channel has been closed, guarded by sending
+	internal      bool       // true for the subscriptions of the methods that wait for a notification
+}
+
+// release any delivery that is blocked on the subscriber, and stop further deliveries from blocking
+func (s *subscription) release() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// close the channel, once any delivery in progress has been released
+func (s *subscription) close() {
+	s.release()
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.notifications)
+	}
+}
+
+type subscriptions struct {
+	sync.RWMutex
+	list   []*subscription
+	closed bool // true once the manager has stopped and the channels have been closed
+}
+
+//
+// Subscribe to the notifications accepted by the filter.
+//
+// The notifications received from the channel are copies that remain valid after
+// delivery, unlike those passed to the NotificationCallback. The node and value of each
+// copy are nil, since those of the network model change as further notifications arrive:
+// use the ids of the copy with the API methods instead.
+//
+// The capacity of the channel is set by SetNotificationBuffer. Once the channel is full,
+// delivery to the subscriber blocks the processing of notifications until the subscriber
+// receives a notification or unsubscribes, so subscribers must receive promptly.
+//
+// The channel is closed by Unsubscribe, or once the manager has stopped and the pending
+// notifications have been drained, so a loop that ranges over the channel terminates when
+// the API shuts down. A channel requested after the manager has stopped is already closed,
+// until the API is run again.
+//
+func (a *api) Subscribe(filter NotificationFilter) <-chan Notification {
+	return a.subscribe(&subscription{
+		filter:        filter,
+		notifications: make(chan Notification, a.notificationBuffer),
+		done:          make(chan struct{}),
+	})
+}
+
+//
+// Subscribe on behalf of a method that waits for a notification.
+//
+// The overflow policy does not apply to these subscriptions: a delivery to a full channel
+// always blocks, since the waiter receives promptly and must not miss the notification it
+// is waiting for, and the channel is buffered even when SetNotificationBuffer is 0.
+//
+func (a *api) subscribeInternal(filter NotificationFilter) <-chan Notification {
+	buffer := a.notificationBuffer
+	if buffer < 1 {
+		buffer = 1
+	}
+	return a.subscribe(&subscription{
+		filter:        filter,
+		notifications: make(chan Notification, buffer),
+		done:          make(chan struct{}),
+		internal:      true,
+	})
+}
+
+// add the subscription, unless the manager has stopped, in which case its channel is closed
+func (a *api) subscribe(s *subscription) <-chan Notification {
+	a.subscriptions.Lock()
+	defer a.subscriptions.Unlock()
+	if a.subscriptions.closed {
+		close(s.notifications)
+		return s.notifications
+	}
+	a.subscriptions.list = append(a.subscriptions.list, s)
+	return s.notifications
+}
+
+//
+// Stop delivery to a channel returned by Subscribe, then close the channel.
+//
+// Unsubscribing a channel that is not subscribed has no effect.
+//
+func (a *api) Unsubscribe(notifications <-chan Notification) {
+	a.subscriptions.RLock()
+	var found *subscription
+	for _, s := range a.subscriptions.list {
+		if s.notifications == notifications {
+			found = s
+			break
+		}
+	}
+	a.subscriptions.RUnlock()
+
+	if found == nil {
+		return
+	}
+
+	// release any delivery that is blocked on this subscriber
+	found.release()
+
+	a.subscriptions.Lock()
+	for i, s := range a.subscriptions.list {
+		if s == found {
+			a.subscriptions.list = append(a.subscriptions.list[:i], a.subscriptions.list[i+1:]...)
+			break
+		}
+	}
+	a.subscriptions.Unlock()
+
+	found.close()
+}
+
+type homeSubscriptions struct {
+	sync.Mutex
+	channels map[uint32]<-chan Notification
+}
+
+//
+// Answer a channel that receives the notifications of a single network.
+//
+// The channel is subscribed when it is first requested, and the same channel is answered
+// until the driver of the network is removed, at which point it is closed. After a driver
+// has been restarted, for example by RestartDriver, a new channel must be requested.
+//
+func (a *api) NotificationsForHome(homeId uint32) <-chan Notification {
+	a.homeSubscriptions.Lock()
+	defer a.homeSubscriptions.Unlock()
+	if notifications, ok := a.homeSubscriptions.channels[homeId]; ok {
+		return notifications
+	}
+	if a.homeSubscriptions.channels == nil {
+		a.homeSubscriptions.channels = make(map[uint32]<-chan Notification)
+	}
+	notifications := a.Subscribe(NotificationFilter{HomeIds: []uint32{homeId}})
+	a.homeSubscriptions.channels[homeId] = notifications
+	return notifications
+}
+
+// close the channel answered by NotificationsForHome for the network, if there is one
+func (a *api) closeHomeNotifications(homeId uint32) {
+	a.homeSubscriptions.Lock()
+	notifications, ok := a.homeSubscriptions.channels[homeId]
+	delete(a.homeSubscriptions.channels, homeId)
+	a.homeSubscriptions.Unlock()
+	if ok {
+		a.Unsubscribe(notifications)
+	}
+}
+
+// deliver a copy of the notification to each subscriber whose filter accepts it, unless it is debounced
+func (a *api) publish(n *notification) {
+	a.subscriptions.RLock()
+	none := len(a.subscriptions.list) == 0
+	a.subscriptions.RUnlock()
+
+	if none {
+		return
+	}
+
+	detached := detachNotification(n)
+	if a.debounce(detached) {
+		return
+	}
+	a.broadcast(detached)
+}
+
+//
+// Deliver the notification to each subscriber whose filter accepts it.
+//
+// The subscribers are copied under the lock and the notification is delivered after it has
+// been released, since a delivery may block on a full channel until the subscriber
+// unsubscribes, which requires the lock.
+//
+func (a *api) broadcast(n Notification) {
+	a.subscriptions.RLock()
+	list := append([]*subscription(nil), a.subscriptions.list...)
+	a.subscriptions.RUnlock()
+
+	for _, s := range list {
+		if s.filter.Accepts(n) {
+			a.deliver(s, n)
+		}
+	}
+}
+
+// accept subscriptions again, at the start of a run that follows one whose channels were closed
+func (a *api) openSubscriptions() {
+	a.subscriptions.Lock()
+	defer a.subscriptions.Unlock()
+	a.subscriptions.closed = false
+}
+
+//
+// Close every subscription channel, once the manager has stopped.
+//
+// The notification callback has been removed by then, so the only remaining deliveries are
+// those of debounced notifications, whose windows may still end. A delivery skips a
+// subscriber whose channel has been closed, so nothing is sent to a closed channel.
+//
+func (a *api) closeSubscriptions() {
+	a.releaseSubscribers()
+
+	a.subscriptions.Lock()
+	for _, s := range a.subscriptions.list {
+		s.close()
+	}
+	a.subscriptions.list = nil
+	a.subscriptions.closed = true
+	a.subscriptions.Unlock()
+
+	a.homeSubscriptions.Lock()
+	a.homeSubscriptions.channels = nil
+	a.homeSubscriptions.Unlock()
+}
+
+// release the deliveries that are blocked on full channels, and stop further deliveries from blocking
+func (a *api) releaseSubscribers() {
+	a.subscriptions.RLock()
+	defer a.subscriptions.RUnlock()
+	for _, s := range a.subscriptions.list {
+		s.release()
+	}
+}
+
+// deliver the notification to the subscriber, applying the overflow policy if its channel is full
+func (a *api) deliver(s *subscription, n Notification) {
+	s.sending.Lock()
+	defer s.sending.Unlock()
+	if s.closed {
+		return
+	}
+
+	if a.overflowPolicy == OVERFLOW_BLOCK || s.internal {
+		select {
+		case s.notifications <- n:
+		case <-s.done:
+		}
+		return
+	}
+
+	select {
+	case s.notifications <- n:
+		return
+	default:
+	}
+
+	if a.overflowPolicy == OVERFLOW_DROP_OLDEST {
+		select {
+		case <-s.notifications:
+			atomic.AddUint64(&a.dropped, 1)
+		default:
+		}
+		select {
+		case s.notifications <- n:
+			return
+		default:
+			// the subscriber is unbuffered, or another notification took the room
+		}
+	}
+
+	atomic.AddUint64(&a.dropped, 1)
+}
+
+// answer the number of notifications discarded because a subscriber's channel was full
+func (a *api) GetDroppedNotifications() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
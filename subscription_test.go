@@ -0,0 +1,62 @@
+package openzwave
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ninjasphere/go-openzwave/NT"
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+func TestUnsubscribeReleasesABlockedDelivery(t *testing.T) {
+	a := &api{overflowPolicy: OVERFLOW_BLOCK}
+	blocked := a.Subscribe(NotificationFilter{})
+
+	delivered := make(chan struct{})
+	go func() {
+		a.broadcast(NewNotification(1, 2, NT.ToEnum(NT.VALUE_CHANGED), nil, 0, VT.ToEnum(VT.BOOL)))
+		close(delivered)
+	}()
+	time.Sleep(10 * time.Millisecond) // let the delivery block on the unbuffered channel
+
+	// a subscriber that arrives while the delivery is blocked must not stop the unsubscribe
+	subscribed := make(chan struct{})
+	go func() {
+		a.Subscribe(NotificationFilter{})
+		close(subscribed)
+	}()
+	time.Sleep(10 * time.Millisecond) // let the subscriber wait for the lock
+
+	unsubscribed := make(chan struct{})
+	go func() {
+		a.Unsubscribe(blocked)
+		close(unsubscribed)
+	}()
+
+	for _, c := range []chan struct{}{subscribed, unsubscribed, delivered} {
+		select {
+		case <-c:
+		case <-time.After(time.Second):
+			t.Fatal("deadlocked while a delivery was blocked on a full channel")
+		}
+	}
+	if _, ok := <-blocked; ok {
+		t.Fatal("expected the unsubscribed channel to be closed")
+	}
+}
+
+func TestInternalSubscriptionIgnoresTheDropPolicy(t *testing.T) {
+	a := &api{overflowPolicy: OVERFLOW_DROP_NEWEST}
+	waiter := a.subscribeInternal(NotificationFilter{})
+
+	a.deliver(a.subscriptions.list[0], NewNotification(1, 2, NT.ToEnum(NT.VALUE_CHANGED), nil, 0, VT.ToEnum(VT.BOOL)))
+
+	select {
+	case <-waiter:
+	default:
+		t.Fatal("expected the notification to be delivered to an unbuffered waiter")
+	}
+	if dropped := a.GetDroppedNotifications(); dropped != 0 {
+		t.Fatalf("expected no dropped notifications, got %d", dropped)
+	}
+}
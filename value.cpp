@@ -99,11 +99,19 @@ bool  getIntValue(uint32_t homeId, uint64_t id, int *value)
 	  return OpenZWave::Manager::Get()->GetValueAsInt(OpenZWave::ValueID(homeId, id), value);
 }
 
+bool  setShortValue(uint32_t homeId, uint64_t id, int16_t value)
+{
+	return OpenZWave::Manager::Get()->SetValue(OpenZWave::ValueID(homeId, id), value);
+}
+
+bool  getShortValue(uint32_t homeId, uint64_t id, int16_t *value)
+{
+	  return OpenZWave::Manager::Get()->GetValueAsShort(OpenZWave::ValueID(homeId, id), value);
+}
+
 bool  setStringValue(uint32_t homeId, uint64_t id, char * value)
 {
-	bool result = OpenZWave::Manager::Get()->SetValue(OpenZWave::ValueID(homeId, id), std::string(value));
-	free(value);
-	return result;
+	return OpenZWave::Manager::Get()->SetValue(OpenZWave::ValueID(homeId, id), std::string(value));
 }
 
 bool  getStringValue(uint32_t homeId, uint64_t id, char ** value)
@@ -118,6 +126,28 @@ bool  getStringValue(uint32_t homeId, uint64_t id, char ** value)
 	  }
 }
 
+bool  getValueFloatPrecision(uint32_t homeId, uint64_t id, uint8_t *precision)
+{
+	  return OpenZWave::Manager::Get()->GetValueFloatPrecision(OpenZWave::ValueID(homeId, id), precision);
+}
+
+bool  setRawValue(uint32_t homeId, uint64_t id, uint8_t * value, uint8_t length)
+{
+	return OpenZWave::Manager::Get()->SetValue(OpenZWave::ValueID(homeId, id), value, length);
+}
+
+// exports the bytes of a raw value into an array that must be released with freeRawValue
+bool  getRawValue(uint32_t homeId, uint64_t id, uint8_t ** value, uint8_t * length)
+{
+	  *value = NULL;
+	  *length = 0;
+	  return OpenZWave::Manager::Get()->GetValueAsRaw(OpenZWave::ValueID(homeId, id), value, length);
+}
+
+void  freeRawValue(uint8_t * value)
+{
+	  delete [] value;
+}
 
 bool refreshValue(uint32_t homeId, uint64_t id)
 {
@@ -132,3 +162,84 @@ bool  setPollingState(uint32_t homeId, uint64_t id, bool state)
     return OpenZWave::Manager::Get()->DisablePoll(OpenZWave::ValueID(homeId, id));
   }
 }
+
+char * getValueLabel(uint32_t homeId, uint64_t id)
+{
+  return strdup(OpenZWave::Manager::Get()->GetValueLabel(OpenZWave::ValueID(homeId, id)).c_str());
+}
+
+char * getValueHelp(uint32_t homeId, uint64_t id)
+{
+  return strdup(OpenZWave::Manager::Get()->GetValueHelp(OpenZWave::ValueID(homeId, id)).c_str());
+}
+
+char * getValueUnits(uint32_t homeId, uint64_t id)
+{
+  return strdup(OpenZWave::Manager::Get()->GetValueUnits(OpenZWave::ValueID(homeId, id)).c_str());
+}
+
+int32_t getValueMin(uint32_t homeId, uint64_t id)
+{
+  return OpenZWave::Manager::Get()->GetValueMin(OpenZWave::ValueID(homeId, id));
+}
+
+int32_t getValueMax(uint32_t homeId, uint64_t id)
+{
+  return OpenZWave::Manager::Get()->GetValueMax(OpenZWave::ValueID(homeId, id));
+}
+
+bool isValueReadOnly(uint32_t homeId, uint64_t id)
+{
+  return OpenZWave::Manager::Get()->IsValueReadOnly(OpenZWave::ValueID(homeId, id));
+}
+
+bool isValueWriteOnly(uint32_t homeId, uint64_t id)
+{
+  return OpenZWave::Manager::Get()->IsValueWriteOnly(OpenZWave::ValueID(homeId, id));
+}
+
+// exports the items of a list value into an array that must be released with freeValueListItems
+bool getValueListItems(uint32_t homeId, uint64_t id, char *** items, int * count)
+{
+  std::vector<std::string> tmp;
+  *items = NULL;
+  *count = 0;
+  if (!OpenZWave::Manager::Get()->GetValueListItems(OpenZWave::ValueID(homeId, id), &tmp)) {
+    return false;
+  }
+  if (tmp.size() > 0) {
+    *items = (char **)malloc(sizeof(char *) * tmp.size());
+    for (size_t i = 0; i < tmp.size(); i++) {
+      (*items)[i] = strdup(tmp[i].c_str());
+    }
+  }
+  *count = tmp.size();
+  return true;
+}
+
+void freeValueListItems(char ** items, int count)
+{
+  if (items) {
+    for (int i = 0; i < count; i++) {
+      free(items[i]);
+    }
+    free(items);
+  }
+}
+
+bool getValueListSelection(uint32_t homeId, uint64_t id, char ** value)
+{
+  std::string tmp;
+  if (OpenZWave::Manager::Get()->GetValueListSelection(OpenZWave::ValueID(homeId, id), &tmp)) {
+    *value = strdup(tmp.c_str());
+    return true;
+  } else {
+    *value = NULL;
+    return false;
+  }
+}
+
+bool setValueListSelection(uint32_t homeId, uint64_t id, char * item)
+{
+  return OpenZWave::Manager::Get()->SetValueListSelection(OpenZWave::ValueID(homeId, id), std::string(item));
+}
@@ -7,17 +7,34 @@ package openzwave
 import "C"
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 	"unsafe"
 
 	"github.com/ninjasphere/go-openzwave/CC"
+	"github.com/ninjasphere/go-openzwave/VG"
 	"github.com/ninjasphere/go-openzwave/VT"
 )
 
+// Returned by GetValueMin and GetValueMax for values that do not define a range.
+var ErrNoValueRange = errors.New("the value does not define a range")
+
+//
+// Identifies a value of a node.
+//
+// CommandClassId, Instance and Index identify the value within its node, and are sufficient
+// for GetValueWithId. The remaining fields are needed to Encode the ValueID as the 64-bit
+// value id used by the API methods.
+//
 type ValueID struct {
 	CommandClassId uint8
 	Instance       uint8
 	Index          uint8
+	HomeId         uint32
+	NodeId         uint8
+	Genre          uint8 // for example, VG.USER
+	Type           uint8 // for example, VT.BOOL
 }
 
 type Value interface {
@@ -38,6 +55,7 @@ type Value interface {
 
 type value struct {
 	cRef *C.Value
+	id   uint64 // the id of the value, set when the value is taken into the network model
 }
 
 type missingValue struct {
@@ -71,7 +89,7 @@ func (v value) String() string {
 }
 
 func newGoValue(cRef *C.Value) *value {
-	return &value{cRef}
+	return &value{cRef: cRef}
 }
 
 func (v *value) notify(api *api, nt *notification) {
@@ -83,6 +101,10 @@ func (v *value) Id() ValueID {
 		CommandClassId: uint8(v.cRef.valueId.commandClassId),
 		Instance:       uint8(v.cRef.valueId.instance),
 		Index:          uint8(v.cRef.valueId.index),
+		HomeId:         uint32(v.cRef.homeId),
+		NodeId:         nodeIdOf(uint64(v.cRef.valueId.id)),
+		Genre:          uint8(valueGenreOf(uint64(v.cRef.valueId.id)).Code),
+		Type:           uint8(v.cRef.valueId.valueType),
 	}
 }
 
@@ -142,6 +164,7 @@ func (v *value) GetString() (string, bool) {
 // for a missing value, the set operation always fails
 func (v *value) SetString(value string) bool {
 	tmp := C.CString(value)
+	defer C.free(unsafe.Pointer(tmp))
 	return (bool)(C.setStringValue(C.uint32_t(v.cRef.homeId), C.uint64_t(v.cRef.valueId.id), tmp))
 }
 
@@ -149,8 +172,12 @@ func (v *value) Refresh() bool {
 	return (bool)(C.refreshValue(C.uint32_t(v.cRef.homeId), C.uint64_t(v.cRef.valueId.id)))
 }
 
+// release the C structure. Subsequent calls have no effect.
 func (v *value) free() {
-	C.freeValue(v.cRef)
+	if v.cRef != nil {
+		C.freeValue(v.cRef)
+		v.cRef = nil
+	}
 }
 
 func (v *value) SetPollingState(state bool) bool {
@@ -217,5 +244,435 @@ func (v *missingValue) SetPollingState(state bool) bool {
 }
 
 func (v *missingValue) Id() ValueID {
-	return ValueID{0, 0, 0}
+	return ValueID{}
+}
+
+// the value type is encoded in the low 4 bits of the value id
+func valueTypeOf(valueId uint64) *VT.Enum {
+	return VT.ToEnum(int(valueId & 0x0f))
+}
+
+// the node id is encoded in bits 24-31 of the value id
+func nodeIdOf(valueId uint64) uint8 {
+	return uint8((valueId & 0xff000000) >> 24)
+}
+
+// the instance is encoded in bits 56-63 of the value id
+func instanceOf(valueId uint64) uint8 {
+	return uint8(valueId >> 56)
+}
+
+// the index is encoded in bits 4-11 of the value id
+func indexOf(valueId uint64) uint8 {
+	return uint8((valueId & 0x00000ff0) >> 4)
+}
+
+// the value genre is encoded in bits 22-23 of the value id
+func valueGenreOf(valueId uint64) *VG.Enum {
+	return VG.ToEnum(int((valueId & 0x00c00000) >> 22))
+}
+
+// the command class is encoded in bits 14-21 of the value id
+func commandClassIdOf(valueId uint64) uint8 {
+	return uint8((valueId & 0x003fc000) >> 14)
+}
+
+func commandClassOf(valueId uint64) *CC.Enum {
+	return CC.ToEnum(int(commandClassIdOf(valueId)))
+}
+
+// the bits of a value id that are not used by any field
+const unusedValueIdBits = 0x00ffffff00003000
+
+//
+// Encode the ValueID as the 64-bit value id used by the API methods.
+//
+// The home id is not part of the encoding, so it must be stored separately to Decode the
+// value id later.
+//
+func (id ValueID) Encode() uint64 {
+	return uint64(id.Instance)<<56 |
+		uint64(id.NodeId)<<24 |
+		uint64(id.Genre&0x03)<<22 |
+		uint64(id.CommandClassId)<<14 |
+		uint64(id.Index)<<4 |
+		uint64(id.Type&0x0f)
+}
+
+// decode a 64-bit value id of the network, answering an error if it is not a valid value id
+func DecodeValueID(homeId uint32, valueId uint64) (ValueID, error) {
+	if valueId&unusedValueIdBits != 0 {
+		return ValueID{}, fmt.Errorf("0x%016x is not a valid value id - unused bits are set", valueId)
+	}
+	if !valueTypeOf(valueId).IsValid() {
+		return ValueID{}, fmt.Errorf("0x%016x is not a valid value id - unknown value type %d", valueId, valueId&0x0f)
+	}
+	return ValueID{
+		CommandClassId: commandClassIdOf(valueId),
+		Instance:       instanceOf(valueId),
+		Index:          indexOf(valueId),
+		HomeId:         homeId,
+		NodeId:         nodeIdOf(valueId),
+		Genre:          uint8(valueGenreOf(valueId).Code),
+		Type:           uint8(valueTypeOf(valueId).Code),
+	}, nil
+}
+
+// the genre of the value
+func (id ValueID) GetGenre() *VG.Enum {
+	return VG.ToEnum(int(id.Genre))
+}
+
+// the command class of the value
+func (id ValueID) GetCommandClass() *CC.Enum {
+	return CC.ToEnum(int(id.CommandClassId))
+}
+
+// the type of the value
+func (id ValueID) GetType() *VT.Enum {
+	return VT.ToEnum(int(id.Type))
+}
+
+// answer an error if the manager has not been started yet
+func checkManager() error {
+	if !(bool)(C.isManagerStarted()) {
+		return ErrManagerNotStarted
+	}
+	return nil
+}
+
+// answer an error if the type of the specified value is not the expected type
+func checkValueType(valueId uint64, expected int) error {
+	actual := valueTypeOf(valueId)
+	if actual.Code != expected {
+		return fmt.Errorf("%w: value 0x%016x has type %v, expected %v", ErrValueTypeMismatch, valueId, actual, VT.ToEnum(expected))
+	}
+	return nil
+}
+
+// answer an error if the manager is not started or the value is not of the expected type
+func checkTypedValue(valueId uint64, expected int) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	return checkValueType(valueId, expected)
+}
+
+// check the manager and the type of the value, then apply the specified setter
+func setTypedValue(valueId uint64, expected int, setter func() bool) error {
+	if err := checkTypedValue(valueId, expected); err != nil {
+		return err
+	}
+	if !setter() {
+		return fmt.Errorf("failed to set value 0x%016x", valueId)
+	}
+	return nil
+}
+
+// set the value of a boolean value
+func (a *api) SetBoolValue(homeId uint32, valueId uint64, value bool) error {
+	return setTypedValue(valueId, VT.BOOL, func() bool {
+		return (bool)(C.setBoolValue(C.uint32_t(homeId), C.uint64_t(valueId), C._Bool(value)))
+	})
+}
+
+// set the value of an integer value
+func (a *api) SetIntValue(homeId uint32, valueId uint64, value int32) error {
+	return setTypedValue(valueId, VT.INT, func() bool {
+		return (bool)(C.setIntValue(C.uint32_t(homeId), C.uint64_t(valueId), C.int(value)))
+	})
+}
+
+// set the value of a byte value
+func (a *api) SetByteValue(homeId uint32, valueId uint64, value uint8) error {
+	return setTypedValue(valueId, VT.BYTE, func() bool {
+		return (bool)(C.setUint8Value(C.uint32_t(homeId), C.uint64_t(valueId), C.uint8_t(value)))
+	})
+}
+
+// set the value of a short value
+func (a *api) SetShortValue(homeId uint32, valueId uint64, value int16) error {
+	return setTypedValue(valueId, VT.SHORT, func() bool {
+		return (bool)(C.setShortValue(C.uint32_t(homeId), C.uint64_t(valueId), C.int16_t(value)))
+	})
+}
+
+// set the value of a string value
+func (a *api) SetStringValue(homeId uint32, valueId uint64, value string) error {
+	cValue := C.CString(value)
+	defer C.free(unsafe.Pointer(cValue))
+	return setTypedValue(valueId, VT.STRING, func() bool {
+		return (bool)(C.setStringValue(C.uint32_t(homeId), C.uint64_t(valueId), cValue))
+	})
+}
+
+// get the value of a boolean value. ok is false if the value is not yet available.
+func (a *api) GetBoolValue(homeId uint32, valueId uint64) (bool, bool, error) {
+	if err := checkTypedValue(valueId, VT.BOOL); err != nil {
+		return false, false, err
+	}
+	var value C._Bool
+	ok := (bool)(C.getBoolValue(C.uint32_t(homeId), C.uint64_t(valueId), (*C._Bool)(&value)))
+	return (bool)(value), ok, nil
+}
+
+// get the value of an integer value. ok is false if the value is not yet available.
+func (a *api) GetIntValue(homeId uint32, valueId uint64) (int32, bool, error) {
+	if err := checkTypedValue(valueId, VT.INT); err != nil {
+		return 0, false, err
+	}
+	var value C.int
+	ok := (bool)(C.getIntValue(C.uint32_t(homeId), C.uint64_t(valueId), (*C.int)(&value)))
+	return (int32)(value), ok, nil
+}
+
+// get the value of a byte value. ok is false if the value is not yet available.
+func (a *api) GetByteValue(homeId uint32, valueId uint64) (uint8, bool, error) {
+	if err := checkTypedValue(valueId, VT.BYTE); err != nil {
+		return 0, false, err
+	}
+	var value C.uint8_t
+	ok := (bool)(C.getUint8Value(C.uint32_t(homeId), C.uint64_t(valueId), (*C.uint8_t)(&value)))
+	return (uint8)(value), ok, nil
+}
+
+// get the value of a short value. ok is false if the value is not yet available.
+func (a *api) getShortValue(homeId uint32, valueId uint64) (int16, bool, error) {
+	if err := checkTypedValue(valueId, VT.SHORT); err != nil {
+		return 0, false, err
+	}
+	var value C.int16_t
+	ok := (bool)(C.getShortValue(C.uint32_t(homeId), C.uint64_t(valueId), (*C.int16_t)(&value)))
+	return (int16)(value), ok, nil
+}
+
+// get the value of a string value. ok is false if the value is not yet available.
+func (a *api) GetStringValue(homeId uint32, valueId uint64) (string, bool, error) {
+	if err := checkTypedValue(valueId, VT.STRING); err != nil {
+		return "", false, err
+	}
+	var value *C.char
+	ok := (bool)(C.getStringValue(C.uint32_t(homeId), C.uint64_t(valueId), (**C.char)(&value)))
+	if ok && value != nil {
+		result := C.GoString(value)
+		C.free(unsafe.Pointer(value))
+		return result, true, nil
+	}
+	return "", false, nil
+}
+
+//
+// Get the value of a decimal value, such as the temperature reported by a multilevel sensor.
+// ok is false if the value is not yet available.
+//
+// OpenZWave keeps decimal values as strings to avoid rounding, so the string is parsed here,
+// independently of the locale, rather than by OpenZWave.
+//
+func (a *api) GetValueAsFloat(homeId uint32, valueId uint64) (float64, bool, error) {
+	value, ok, err := a.getDecimalValue(homeId, valueId)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	result, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse decimal value 0x%016x: %v", valueId, err)
+	}
+	return result, true, nil
+}
+
+// get the string representation of a decimal value
+func (a *api) getDecimalValue(homeId uint32, valueId uint64) (string, bool, error) {
+	if err := checkTypedValue(valueId, VT.DECIMAL); err != nil {
+		return "", false, err
+	}
+	var value *C.char
+	ok := (bool)(C.getStringValue(C.uint32_t(homeId), C.uint64_t(valueId), (**C.char)(&value)))
+	if !ok || value == nil {
+		return "", false, nil
+	}
+	return takeString(value), true, nil
+}
+
+//
+// Set the value of a decimal value.
+//
+// The value is rounded to the precision of the current value, so that, for example, a
+// thermostat set point with a precision of 1 is sent as 21.5 rather than 21.500000.
+//
+func (a *api) SetDecimalValue(homeId uint32, valueId uint64, value float64) error {
+	if err := checkTypedValue(valueId, VT.DECIMAL); err != nil {
+		return err
+	}
+	var precision C.uint8_t
+	if !(bool)(C.getValueFloatPrecision(C.uint32_t(homeId), C.uint64_t(valueId), (*C.uint8_t)(&precision))) {
+		precision = 0
+	}
+	cValue := C.CString(strconv.FormatFloat(value, 'f', int(precision), 64))
+	defer C.free(unsafe.Pointer(cValue))
+	if !(bool)(C.setStringValue(C.uint32_t(homeId), C.uint64_t(valueId), cValue)) {
+		return fmt.Errorf("failed to set value 0x%016x", valueId)
+	}
+	return nil
+}
+
+// get the bytes of a raw value, such as a user code of a door lock
+func (a *api) GetValueAsRaw(homeId uint32, valueId uint64) ([]byte, error) {
+	if err := checkTypedValue(valueId, VT.RAW); err != nil {
+		return nil, err
+	}
+	var value *C.uint8_t
+	var length C.uint8_t
+	ok := (bool)(C.getRawValue(C.uint32_t(homeId), C.uint64_t(valueId), &value, &length))
+	defer C.freeRawValue(value)
+	if !ok {
+		return nil, fmt.Errorf("%w: value 0x%016x is not available", ErrValueNotFound, valueId)
+	}
+	if value == nil {
+		return []byte{}, nil
+	}
+	return C.GoBytes(unsafe.Pointer(value), C.int(length)), nil
+}
+
+// set the bytes of a raw value. At most 255 bytes may be set.
+func (a *api) SetRawValue(homeId uint32, valueId uint64, value []byte) error {
+	if len(value) > 0xff {
+		return fmt.Errorf("raw value of %d bytes is too long for value 0x%016x", len(value), valueId)
+	}
+	cValue := (*C.uint8_t)(C.CBytes(value))
+	defer C.free(unsafe.Pointer(cValue))
+	return setTypedValue(valueId, VT.RAW, func() bool {
+		return (bool)(C.setRawValue(C.uint32_t(homeId), C.uint64_t(valueId), cValue, C.uint8_t(len(value))))
+	})
+}
+
+// get any value rendered as a string, regardless of its underlying type
+func (a *api) GetValueAsString(homeId uint32, valueId uint64) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	var value *C.char
+	ok := (bool)(C.getStringValue(C.uint32_t(homeId), C.uint64_t(valueId), (**C.char)(&value)))
+	if !ok || value == nil {
+		return "", fmt.Errorf("%w: value 0x%016x is not available", ErrValueNotFound, valueId)
+	}
+	defer C.free(unsafe.Pointer(value))
+	return C.GoString(value), nil
+}
+
+// get the human-readable label of a value
+func (a *api) GetValueLabel(homeId uint32, valueId uint64) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getValueLabel(C.uint32_t(homeId), C.uint64_t(valueId))), nil
+}
+
+// get the help text of a value
+func (a *api) GetValueHelp(homeId uint32, valueId uint64) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getValueHelp(C.uint32_t(homeId), C.uint64_t(valueId))), nil
+}
+
+// get the units of a value, for example "C", "%" or "W". Values without units answer an empty string.
+func (a *api) GetValueUnits(homeId uint32, valueId uint64) (string, error) {
+	if err := checkManager(); err != nil {
+		return "", err
+	}
+	return takeString(C.getValueUnits(C.uint32_t(homeId), C.uint64_t(valueId))), nil
+}
+
+// get the range of a value. OpenZWave reports min == max == 0 for values without a range.
+func getValueRange(homeId uint32, valueId uint64) (int32, int32, error) {
+	if err := checkManager(); err != nil {
+		return 0, 0, err
+	}
+	min := (int32)(C.getValueMin(C.uint32_t(homeId), C.uint64_t(valueId)))
+	max := (int32)(C.getValueMax(C.uint32_t(homeId), C.uint64_t(valueId)))
+	if min == max {
+		return 0, 0, ErrNoValueRange
+	}
+	return min, max, nil
+}
+
+// get the minimum of a value
+func (a *api) GetValueMin(homeId uint32, valueId uint64) (int32, error) {
+	min, _, err := getValueRange(homeId, valueId)
+	return min, err
+}
+
+// get the maximum of a value
+func (a *api) GetValueMax(homeId uint32, valueId uint64) (int32, error) {
+	_, max, err := getValueRange(homeId, valueId)
+	return max, err
+}
+
+// answer an error if the value is not known to the network model
+func (a *api) checkValueKnown(homeId uint32, valueId uint64) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	if !a.isValueKnown(homeId, valueId) {
+		return fmt.Errorf("%w: value 0x%016x is not known in network 0x%08x", ErrValueNotFound, valueId, homeId)
+	}
+	return nil
+}
+
+// answer true if the value is read only, for example, a sensor reading
+func (a *api) IsValueReadOnly(homeId uint32, valueId uint64) (bool, error) {
+	if err := a.checkValueKnown(homeId, valueId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isValueReadOnly(C.uint32_t(homeId), C.uint64_t(valueId))), nil
+}
+
+// answer true if the value is write only
+func (a *api) IsValueWriteOnly(homeId uint32, valueId uint64) (bool, error) {
+	if err := a.checkValueKnown(homeId, valueId); err != nil {
+		return false, err
+	}
+	return (bool)(C.isValueWriteOnly(C.uint32_t(homeId), C.uint64_t(valueId))), nil
+}
+
+// get the items of a list value
+func (a *api) GetValueListItems(homeId uint32, valueId uint64) ([]string, error) {
+	if err := checkTypedValue(valueId, VT.LIST); err != nil {
+		return nil, err
+	}
+	var (
+		items **C.char
+		count C.int
+	)
+	if !(bool)(C.getValueListItems(C.uint32_t(homeId), C.uint64_t(valueId), &items, &count)) {
+		return nil, fmt.Errorf("failed to get the items of value 0x%016x", valueId)
+	}
+	defer C.freeValueListItems(items, count)
+
+	result := make([]string, int(count))
+	for i, item := range unsafe.Slice(items, int(count)) {
+		result[i] = C.GoString(item)
+	}
+	return result, nil
+}
+
+// get the currently selected item of a list value
+func (a *api) GetValueListSelection(homeId uint32, valueId uint64) (string, error) {
+	if err := checkTypedValue(valueId, VT.LIST); err != nil {
+		return "", err
+	}
+	var value *C.char
+	if !(bool)(C.getValueListSelection(C.uint32_t(homeId), C.uint64_t(valueId), &value)) || value == nil {
+		return "", fmt.Errorf("failed to get the selection of value 0x%016x", valueId)
+	}
+	return takeString(value), nil
+}
+
+// select an item of a list value
+func (a *api) SetValueListSelection(homeId uint32, valueId uint64, item string) error {
+	cItem := C.CString(item)
+	defer C.free(unsafe.Pointer(cItem))
+	return setTypedValue(valueId, VT.LIST, func() bool {
+		return (bool)(C.setValueListSelection(C.uint32_t(homeId), C.uint64_t(valueId), cItem))
+	})
 }
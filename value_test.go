@@ -0,0 +1,47 @@
+package openzwave
+
+import (
+	"testing"
+
+	"github.com/ninjasphere/go-openzwave/CC"
+	"github.com/ninjasphere/go-openzwave/VG"
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+func TestValueIDRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		valueId uint64
+		decoded ValueID
+	}{
+		{"binary switch", 0x0100000005494000, ValueID{CommandClassId: CC.SWITCH_BINARY, Instance: 1, Index: 0, HomeId: 0x12345678, NodeId: 5, Genre: VG.USER, Type: VT.BOOL}},
+		{"every instance, genre and index bit", 0xff000000e8dc0ff9, ValueID{CommandClassId: CC.CONFIGURATION, Instance: 0xff, Index: 0xff, HomeId: 0x12345678, NodeId: 0xe8, Genre: VG.SYSTEM, Type: VT.RAW}},
+		{"second instance of a sensor", 0x02000000018c4053, ValueID{CommandClassId: CC.SENSOR_MULTILEVEL, Instance: 2, Index: 5, HomeId: 0x12345678, NodeId: 1, Genre: VG.CONFIG, Type: VT.INT}},
+		{"highest index bit", 0x01000000ff3fc807, ValueID{CommandClassId: 0xff, Instance: 1, Index: 0x80, HomeId: 0x12345678, NodeId: 0xff, Genre: VG.BASIC, Type: VT.STRING}},
+	}
+	for _, test := range tests {
+		decoded, err := DecodeValueID(0x12345678, test.valueId)
+		if err != nil {
+			t.Errorf("%s: failed to decode 0x%016x: %v", test.name, test.valueId, err)
+			continue
+		}
+		if decoded != test.decoded {
+			t.Errorf("%s: decoded 0x%016x as %+v, expected %+v", test.name, test.valueId, decoded, test.decoded)
+		}
+		if encoded := decoded.Encode(); encoded != test.valueId {
+			t.Errorf("%s: encoded %+v as 0x%016x, expected 0x%016x", test.name, decoded, encoded, test.valueId)
+		}
+	}
+}
+
+func TestDecodeInvalidValueID(t *testing.T) {
+	for _, valueId := range []uint64{
+		0x0100000005495000, // unused bits 12-13 set
+		0x0110000005494000, // unused bits 32-55 set
+		0x010000000549400f, // unknown value type
+	} {
+		if _, err := DecodeValueID(0x12345678, valueId); err == nil {
+			t.Errorf("expected 0x%016x to be rejected", valueId)
+		}
+	}
+}
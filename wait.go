@@ -0,0 +1,197 @@
+package openzwave
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ninjasphere/go-openzwave/NT"
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+//
+// Wait until the queries of a node are complete, or the context is done.
+//
+// This answers immediately if the queries of the node are already complete, otherwise it
+// waits for a NODE_QUERIES_COMPLETE notification for the node. Sleeping nodes only complete
+// their queries when they next wake up, so the context should normally have a deadline.
+//
+// Like the other methods that wait for notifications, this MUST NOT be called from the
+// NotificationCallback, since the notification it is waiting for can only be delivered
+// once the callback has returned.
+//
+func (a *api) WaitForNodeReady(ctx context.Context, homeId uint32, nodeId uint8) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+
+	// subscribe before checking the stage, so that the completion cannot be missed
+	ready := a.subscribeInternal(NotificationFilter{
+		HomeIds:           []uint32{homeId},
+		NodeIds:           []uint8{nodeId},
+		NotificationTypes: []int{NT.NODE_QUERIES_COMPLETE},
+	})
+	defer a.Unsubscribe(ready)
+
+	if stage, err := a.GetNodeQueryStage(homeId, nodeId); err != nil {
+		return err
+	} else if stage == "Complete" {
+		return nil
+	}
+
+	select {
+	case _, ok := <-ready:
+		if !ok {
+			return fmt.Errorf("stopped waiting for node %d in network 0x%08x", nodeId, homeId)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+//
+// Wait for the next VALUE_CHANGED notification for a value, or until the context is done.
+//
+// The notification that changed the value is answered. Its value is nil, like that of
+// any subscribed notification, so the new value is read with CachedValue, which already
+// holds it when the value cache is enabled, or with the getter for its type. This MUST
+// NOT be called from the NotificationCallback.
+//
+func (a *api) WaitForValueChange(ctx context.Context, homeId uint32, valueId uint64) (Notification, error) {
+	if err := checkManager(); err != nil {
+		return nil, err
+	}
+	changes := subscribeToValue(a, homeId, valueId, NT.VALUE_CHANGED)
+	defer a.Unsubscribe(changes)
+	return awaitValueChange(ctx, changes, valueId)
+}
+
+// subscribe to the notifications of the specified types for the node of a value, on behalf of a waiter
+func subscribeToValue(subscriber API, homeId uint32, valueId uint64, notificationTypes ...int) <-chan Notification {
+	filter := NotificationFilter{
+		HomeIds:           []uint32{homeId},
+		NodeIds:           []uint8{nodeIdOf(valueId)},
+		CommandClasses:    []uint8{commandClassIdOf(valueId)},
+		NotificationTypes: notificationTypes,
+	}
+	if a, ok := subscriber.(*api); ok {
+		return a.subscribeInternal(filter)
+	}
+	return subscriber.Subscribe(filter)
+}
+
+// answer the first notification received for the value, skipping those for other values of the node
+func awaitValueChange(ctx context.Context, changes <-chan Notification, valueId uint64) (Notification, error) {
+	for {
+		select {
+		case nt, ok := <-changes:
+			if !ok {
+				return nil, fmt.Errorf("stopped waiting for value 0x%016x", valueId)
+			}
+			if nt.GetValueId() == valueId {
+				return nt, nil
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+//
+// Set the value of a boolean value, then wait until the device reports that it has applied
+// the change, or until the context is done.
+//
+// SetBoolValue answers as soon as OpenZWave has queued the command, which may be long before
+// a sleeping device receives it, if it ever does. The report is either a VALUE_CHANGED
+// notification, or a VALUE_REFRESHED notification if the value was already set, and the value
+// read back after it must match. An error is returned if the device reports a different value,
+// for example because a lock jammed. This MUST NOT be called from the NotificationCallback.
+//
+func (a *api) SetBoolValueAck(ctx context.Context, homeId uint32, valueId uint64, value bool) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	return SetBoolValueAckWith(ctx, a, homeId, valueId, value)
+}
+
+//
+// Set the value of a boolean value through another implementation of API, such as a mock,
+// then wait for the device to apply the change as SetBoolValueAck does.
+//
+// Only the Subscribe, Unsubscribe, SetBoolValue and GetBoolValue methods of the API are used.
+//
+func SetBoolValueAckWith(ctx context.Context, api API, homeId uint32, valueId uint64, value bool) error {
+	if err := checkValueType(valueId, VT.BOOL); err != nil {
+		return err
+	}
+
+	// subscribe before the value is set, so that the report cannot be missed
+	changes := subscribeToValue(api, homeId, valueId, NT.VALUE_CHANGED, NT.VALUE_REFRESHED)
+	defer api.Unsubscribe(changes)
+
+	if err := api.SetBoolValue(homeId, valueId, value); err != nil {
+		return err
+	}
+	if _, err := awaitValueChange(ctx, changes, valueId); err != nil {
+		return err
+	}
+
+	actual, ok, err := api.GetBoolValue(homeId, valueId)
+	if err != nil {
+		return err
+	}
+	if !ok || actual != value {
+		return fmt.Errorf("value 0x%016x was not set to %v by the device", valueId, value)
+	}
+	return nil
+}
+
+//
+// Set the value of a boolean value, retrying with exponential backoff until the device
+// reports that it has applied the change, or the attempts are exhausted.
+//
+// Each attempt waits up to the current backoff for the device to report the change, and
+// the backoff doubles after each attempt, so that a busy network has time to settle. An
+// attempt that fails immediately, because the command could not be queued, still waits
+// out its backoff before the next attempt. The error of the last attempt is returned if
+// every attempt fails. This MUST NOT be called from the NotificationCallback.
+//
+func (a *api) SetBoolValueRetry(homeId uint32, valueId uint64, value bool, attempts int, backoff time.Duration) error {
+	if err := checkManager(); err != nil {
+		return err
+	}
+	return SetBoolValueRetryWith(a, homeId, valueId, value, attempts, backoff)
+}
+
+//
+// Set the value of a boolean value through another implementation of API, such as a mock,
+// retrying as SetBoolValueRetry does.
+//
+// The Logger method of the API is used as well as those used by SetBoolValueAckWith.
+//
+func SetBoolValueRetryWith(api API, homeId uint32, valueId uint64, value bool, attempts int, backoff time.Duration) error {
+	if attempts < 1 {
+		return fmt.Errorf("at least one attempt is required to set value 0x%016x", valueId)
+	}
+	if err := checkValueType(valueId, VT.BOOL); err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), backoff)
+		err := SetBoolValueAckWith(ctx, api, homeId, valueId, value)
+		if err == nil {
+			cancel()
+			return nil
+		}
+		if attempt == attempts {
+			cancel()
+			return fmt.Errorf("failed to set value 0x%016x after %d attempts: %w", valueId, attempts, err)
+		}
+		api.Logger().Debugf("attempt %d to set value 0x%016x failed, retrying: %v\n", attempt, valueId, err)
+		<-ctx.Done()
+		cancel()
+		backoff *= 2
+	}
+}
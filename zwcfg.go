@@ -0,0 +1,183 @@
+package openzwave
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/ninjasphere/go-openzwave/VG"
+	"github.com/ninjasphere/go-openzwave/VT"
+)
+
+// the names of the value types in zwcfg_*.xml, in the order of their codes
+var zwcfgTypeNames = []string{"bool", "byte", "decimal", "int", "list", "schedule", "short", "string", "button", "raw"}
+
+// the names of the value genres in zwcfg_*.xml, in the order of their codes. OpenZWave 1.0 writes the basic genre as "all".
+var zwcfgGenreNames = []string{"all", "user", "config", "system"}
+
+type zwcfgDriver struct {
+	HomeId string      `xml:"home_id,attr"`
+	Nodes  []zwcfgNode `xml:"Node"`
+}
+
+type zwcfgNode struct {
+	Id           uint8  `xml:"id,attr"`
+	Name         string `xml:"name,attr"`
+	Location     string `xml:"location,attr"`
+	Type         string `xml:"type,attr"`
+	Manufacturer struct {
+		Id      string `xml:"id,attr"`
+		Name    string `xml:"name,attr"`
+		Product struct {
+			Type string `xml:"type,attr"`
+			Id   string `xml:"id,attr"`
+			Name string `xml:"name,attr"`
+		} `xml:"Product"`
+	} `xml:"Manufacturer"`
+	CommandClasses []zwcfgCommandClass `xml:"CommandClasses>CommandClass"`
+}
+
+type zwcfgCommandClass struct {
+	Id     uint8        `xml:"id,attr"`
+	Values []zwcfgValue `xml:"Value"`
+}
+
+type zwcfgValue struct {
+	Type     string `xml:"type,attr"`
+	Genre    string `xml:"genre,attr"`
+	Instance uint8  `xml:"instance,attr"`
+	Index    uint8  `xml:"index,attr"`
+	Label    string `xml:"label,attr"`
+	Units    string `xml:"units,attr"`
+	Value    string `xml:"value,attr"`
+	VIndex   int    `xml:"vindex,attr"` // the index of the selected item of a list value
+	Items    []struct {
+		Label string `xml:"label,attr"`
+	} `xml:"Item"`
+	Help string `xml:"Help"`
+}
+
+//
+// Read the networks that OpenZWave saved in the zwcfg_<homeid>.xml files of the user path,
+// without starting the manager.
+//
+// This allows the last known state of the networks to be shown while OpenZWave queries the
+// devices again. The snapshot has the same form as one taken by Snapshot, except that IsSet
+// is false, since no value has been confirmed by its device, and each value is formatted as
+// OpenZWave saved it. A network whose file cannot be parsed answers an error. If the user path
+// contains no zwcfg_*.xml files, the snapshot has no homes.
+//
+func ParseConfig(userPath string) (NetworkSnapshot, error) {
+	snapshot := NetworkSnapshot{Homes: []HomeSnapshot{}}
+	paths, err := filepath.Glob(filepath.Join(userPath, "zwcfg_0x*.xml"))
+	if err != nil {
+		return snapshot, err
+	}
+	for _, path := range paths {
+		home, err := parseConfigFile(path)
+		if err != nil {
+			return NetworkSnapshot{}, err
+		}
+		snapshot.Homes = append(snapshot.Homes, home)
+	}
+	sort.Slice(snapshot.Homes, func(i, j int) bool { return snapshot.Homes[i].HomeId < snapshot.Homes[j].HomeId })
+	return snapshot, nil
+}
+
+// read a single network from a zwcfg_<homeid>.xml file
+func parseConfigFile(path string) (HomeSnapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return HomeSnapshot{}, err
+	}
+	var driver zwcfgDriver
+	if err := xml.Unmarshal(data, &driver); err != nil {
+		return HomeSnapshot{}, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	homeId, err := strconv.ParseUint(driver.HomeId, 0, 32)
+	if err != nil {
+		return HomeSnapshot{}, fmt.Errorf("failed to parse %s: invalid home id %q", path, driver.HomeId)
+	}
+
+	home := HomeSnapshot{HomeId: uint32(homeId), Nodes: []NodeSnapshot{}}
+	for _, n := range driver.Nodes {
+		home.Nodes = append(home.Nodes, n.snapshot(home.HomeId))
+	}
+	sort.Slice(home.Nodes, func(i, j int) bool { return home.Nodes[i].NodeId < home.Nodes[j].NodeId })
+	return home, nil
+}
+
+func (n *zwcfgNode) snapshot(homeId uint32) NodeSnapshot {
+	result := NodeSnapshot{
+		NodeId:           n.Id,
+		Name:             n.Name,
+		Location:         n.Location,
+		NodeType:         n.Type,
+		ManufacturerName: n.Manufacturer.Name,
+		ManufacturerId:   n.Manufacturer.Id,
+		ProductName:      n.Manufacturer.Product.Name,
+		ProductType:      n.Manufacturer.Product.Type,
+		ProductId:        n.Manufacturer.Product.Id,
+		Values:           []ValueSnapshot{},
+	}
+	for _, class := range n.CommandClasses {
+		for _, v := range class.Values {
+			if snapshot, ok := v.snapshot(homeId, n.Id, class.Id); ok {
+				result.Values = append(result.Values, snapshot)
+			}
+		}
+	}
+	sort.Slice(result.Values, func(i, j int) bool { return result.Values[i].ValueId < result.Values[j].ValueId })
+	return result
+}
+
+// answer false if the type of the value is unknown, since its value id cannot then be encoded
+func (v *zwcfgValue) snapshot(homeId uint32, nodeId uint8, commandClassId uint8) (ValueSnapshot, bool) {
+	valueType := indexOfName(zwcfgTypeNames, v.Type)
+	if valueType < 0 {
+		return ValueSnapshot{}, false
+	}
+	genre := indexOfName(zwcfgGenreNames, v.Genre)
+	if genre < 0 {
+		genre = VG.SYSTEM // as OpenZWave does for an unknown genre
+	}
+
+	id := ValueID{
+		CommandClassId: commandClassId,
+		Instance:       v.Instance,
+		Index:          v.Index,
+		HomeId:         homeId,
+		NodeId:         nodeId,
+		Genre:          uint8(genre),
+		Type:           uint8(valueType),
+	}
+	value := v.Value
+	if valueType == VT.LIST && v.VIndex >= 0 && v.VIndex < len(v.Items) {
+		value = v.Items[v.VIndex].Label
+	}
+	return ValueSnapshot{
+		ValueId:      id.Encode(),
+		CommandClass: id.GetCommandClass().String(),
+		Instance:     v.Instance,
+		Index:        v.Index,
+		Type:         id.GetType().String(),
+		Genre:        id.GetGenre().String(),
+		Label:        v.Label,
+		Value:        value,
+		Units:        v.Units,
+		Help:         v.Help,
+	}, true
+}
+
+// answer the index of the name in the list, or -1 if it is not present
+func indexOfName(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}